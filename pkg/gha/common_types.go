@@ -1,5 +1,12 @@
 package gha
 
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 // The ID of a hotel, using the same ID as the Hotel List Feed. The number of
 // <Property> elements you can specify in a single <Item> block is determined
 // by the type of Hint Response message:
@@ -15,8 +22,95 @@ type Property struct {
 	ID string `xml:",chardata"`
 }
 
-// Represents an amount of money with its currency type.
+// Boolean that is marshalled as 1 or 0, as in the Transaction examples of
+// Google. Both 1/0 and true/false are accepted when unmarshalling.
+type Bool bool
+
+func (b Bool) MarshalText() ([]byte, error) {
+	if b {
+		return []byte("1"), nil
+	}
+	return []byte("0"), nil
+}
+
+// Whether something such as breakfast is included, for optional elements
+// such as <BreakfastIncluded> that are written if set, even if false. Google
+// accepts 1/0 and true/false; the form a value is read in is kept, so that a
+// message is written as it is read.
+type Inclusion struct {
+	Included bool
+	Words    bool // written as true/false instead of 1/0
+}
+
+// Returns a new inclusion that is written as 1 or 0.
+func NewInclusion(included bool) *Inclusion {
+	return &Inclusion{Included: included}
+}
+
+func (i Inclusion) MarshalText() ([]byte, error) {
+	if i.Words {
+		return []byte(strconv.FormatBool(i.Included)), nil
+	}
+	return Bool(i.Included).MarshalText()
+}
+
+func (i *Inclusion) UnmarshalText(text []byte) error {
+	switch s := strings.TrimSpace(string(text)); s {
+	case "1", "0":
+		*i = Inclusion{Included: s == "1"}
+	case "true", "false":
+		*i = Inclusion{Included: s == "true", Words: true}
+	default:
+		return fmt.Errorf("gha: invalid boolean %q", s)
+	}
+	return nil
+}
+
+// Empty element such as <NoVacancy/> that is either present or not.
+type Flag bool
+
+// Writes the empty element if the flag is set, and nothing otherwise.
+func (f Flag) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if !f {
+		return nil
+	}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	return e.EncodeToken(start.End())
+}
+
+func (f *Flag) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	*f = true
+	return d.Skip()
+}
+
+// Represents an amount of money with its currency type. The amount is an
+// exact decimal that keeps the number of fractional digits it is given with.
 type Money struct {
-	Value    float32 `xml:",chardata"`
-	Currency string  `xml:"currency,attr"`
+	Value    Decimal `xml:",chardata"`
+	Currency string  `xml:"currency,attr"` // ISO 4217, e.g. "USD"
+}
+
+// A text in a specific language.
+type Text struct {
+	Text     string `xml:"text,attr"`
+	Language string `xml:"language,attr"` // ISO 639-1, e.g. "en"
+}
+
+// Container for one or more translations of the same text, such as the
+// name or the description of a room.
+type LocalizedText struct {
+	Text []Text `xml:",omitempty"`
+}
+
+// Returns the text for the given language or an empty string if no such
+// translation exists.
+func (l *LocalizedText) Get(language string) string {
+	for _, t := range l.Text {
+		if t.Language == language {
+			return t.Text
+		}
+	}
+	return ""
 }
@@ -1,10 +1,42 @@
 package gha
 
 import (
+	"bytes"
 	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
 	"testing"
 )
 
+// Opens the given file of testdata and passes it to decode.
+func readTestFile(t *testing.T, name string, decode func(io.Reader) error) {
+	t.Helper()
+	f, err := os.Open("./testdata/" + name)
+	if err != nil {
+		t.Fatalf("File reading error %v", err)
+	}
+	defer f.Close()
+
+	if err := decode(f); err != nil {
+		t.Fatalf("Parsing file failed with error: %v", err)
+	}
+}
+
+// Checks that encode writes exactly the given file of testdata.
+func checkEncodeFile(t *testing.T, name string, encode func(io.Writer) error) {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := encode(&buf); err != nil {
+		t.Fatalf("Encoding file failed with error: %v", err)
+	}
+	want, _ := ioutil.ReadFile("./testdata/" + name)
+	if buf.String() != string(want) {
+		t.Errorf("Encode got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
 func TestPropertyStruct(t *testing.T) {
 	example := "<Property>abc</Property>"
 
@@ -18,6 +50,16 @@ func TestPropertyStruct(t *testing.T) {
 	}
 }
 
+// Returns the Money for the given amount and currency. It panics if the
+// amount is not a valid decimal.
+func money(value, currency string) *Money {
+	m, err := NewMoney(value, currency)
+	if err != nil {
+		panic(err)
+	}
+	return &m
+}
+
 func TestMoneyStruct(t *testing.T) {
 	example := "<Money currency=\"USD\">13.54</Money>"
 
@@ -26,10 +68,85 @@ func TestMoneyStruct(t *testing.T) {
 		t.Errorf("Unmarshal data failed. %v", err)
 	}
 
-	if m.Value != 13.54 {
+	if m.Value.String() != "13.54" {
 		t.Errorf("Value got %v, want: 13.54", m.Value)
 	}
 	if m.Currency != "USD" {
 		t.Errorf("ID got %v, want: USD", m.Currency)
 	}
 }
+
+func TestLocalizedTextStruct(t *testing.T) {
+	example := "<Name><Text text=\"Double Room\" language=\"en\"/><Text text=\"Doppelzimmer\" language=\"de\"/></Name>"
+
+	var l LocalizedText
+	if err := xml.Unmarshal([]byte(example), &l); err != nil {
+		t.Errorf("Unmarshal data failed. %v", err)
+	}
+
+	if got := l.Get("de"); got != "Doppelzimmer" {
+		t.Errorf("Get(de) got %v, want: Doppelzimmer", got)
+	}
+	if got := l.Get("fr"); got != "" {
+		t.Errorf("Get(fr) got %v, want empty string", got)
+	}
+}
+
+func TestBoolStruct(t *testing.T) {
+	type flags struct {
+		A Bool `xml:"a,attr"`
+		B Bool `xml:""`
+		C Bool `xml:",omitempty"`
+	}
+
+	for _, example := range []string{
+		`<flags a="true"><B>false</B><C>true</C></flags>`,
+		`<flags a="1"><B>0</B><C>1</C></flags>`,
+	} {
+		var f flags
+		if err := xml.Unmarshal([]byte(example), &f); err != nil {
+			t.Errorf("Unmarshal data failed. %v", err)
+			continue
+		}
+		if !f.A || f.B || !f.C {
+			t.Errorf("Unmarshal of %s got %+v", example, f)
+		}
+	}
+
+	got, err := xml.Marshal(flags{A: true})
+	if err != nil {
+		t.Fatalf("Marshal data failed. %v", err)
+	}
+	if want := `<flags a="1"><B>0</B></flags>`; string(got) != want {
+		t.Errorf("Marshal got %s, want: %s", got, want)
+	}
+}
+
+func TestInclusionStruct(t *testing.T) {
+	type bundle struct {
+		Breakfast *Inclusion `xml:",omitempty"`
+		Parking   *Inclusion `xml:",omitempty"`
+		Internet  *Inclusion `xml:",omitempty"`
+	}
+
+	example := `<bundle><Breakfast>1</Breakfast><Parking>false</Parking></bundle>`
+	var b bundle
+	if err := xml.Unmarshal([]byte(example), &b); err != nil {
+		t.Fatalf("Unmarshal data failed. %v", err)
+	}
+	if want := (bundle{Breakfast: NewInclusion(true), Parking: &Inclusion{Words: true}}); !reflect.DeepEqual(b, want) {
+		printError(t, b, want)
+	}
+
+	got, err := xml.Marshal(b)
+	if err != nil {
+		t.Fatalf("Marshal data failed. %v", err)
+	}
+	if string(got) != example {
+		t.Errorf("Marshal got %s, want: %s", got, example)
+	}
+
+	if err := xml.Unmarshal([]byte(`<bundle><Breakfast>yes</Breakfast></bundle>`), &b); err == nil {
+		t.Error("Unmarshal of yes got no error")
+	}
+}
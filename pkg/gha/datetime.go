@@ -0,0 +1,159 @@
+package gha
+
+import (
+	"encoding/xml"
+	"strings"
+	"time"
+)
+
+// Formats of the date and time values of the Google Hotels API.
+//
+// https://developers.google.com/hotels/hotel-prices/xml-reference/datetime
+const (
+	DateFormat     = "2006-01-02"
+	TimeFormat     = "15:04"
+	DateTimeFormat = time.RFC3339
+)
+
+// A date without time of day, e.g. "2018-06-10". The zero value is omitted
+// when marshalled, so unset dates do not show up as "0001-01-01".
+type Date time.Time
+
+// Parses a date in the format "YYYY-MM-DD".
+func NewDate(value string) (Date, error) {
+	d, err := time.Parse(DateFormat, strings.TrimSpace(value))
+	return Date(d), err
+}
+
+// Returns true for the zero value.
+func (d Date) IsZero() bool {
+	return time.Time(d).IsZero()
+}
+
+func (d Date) String() string {
+	return time.Time(d).Format(DateFormat)
+}
+
+func (d Date) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return marshalElement(e, start, d.IsZero(), d.String())
+}
+
+func (d Date) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return marshalAttr(name, d.IsZero(), d.String())
+}
+
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Date) UnmarshalText(text []byte) error {
+	v, err := NewDate(string(text))
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// A time of day in the local time of the hotel, e.g. "18:00". The zero value
+// stands for an unset time and is omitted when marshalled; "00:00" is kept.
+type Time time.Time
+
+// Parses a time of day in the format "HH:MM".
+func NewTime(value string) (Time, error) {
+	t, err := time.Parse(TimeFormat, strings.TrimSpace(value))
+	return Time(t), err
+}
+
+// Returns true for the zero value.
+func (t Time) IsZero() bool {
+	return time.Time(t).IsZero()
+}
+
+// Returns the hour and minute of the time.
+func (t Time) Clock() (hour, min int) {
+	hour, min, _ = time.Time(t).Clock()
+	return hour, min
+}
+
+func (t Time) String() string {
+	return time.Time(t).Format(TimeFormat)
+}
+
+func (t Time) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return marshalElement(e, start, t.IsZero(), t.String())
+}
+
+func (t Time) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return marshalAttr(name, t.IsZero(), t.String())
+}
+
+func (t Time) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *Time) UnmarshalText(text []byte) error {
+	v, err := NewTime(string(text))
+	if err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+// A point in time with time zone offset, e.g. "2017-07-18T16:20:00-04:00".
+// The zero value is omitted when marshalled.
+type DateTime time.Time
+
+// Parses a date and time in RFC 3339 format.
+func NewDateTime(value string) (DateTime, error) {
+	t, err := time.Parse(DateTimeFormat, strings.TrimSpace(value))
+	return DateTime(t), err
+}
+
+// Returns true for the zero value.
+func (t DateTime) IsZero() bool {
+	return time.Time(t).IsZero()
+}
+
+func (t DateTime) String() string {
+	return time.Time(t).Format(DateTimeFormat)
+}
+
+func (t DateTime) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return marshalElement(e, start, t.IsZero(), t.String())
+}
+
+func (t DateTime) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return marshalAttr(name, t.IsZero(), t.String())
+}
+
+func (t DateTime) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *DateTime) UnmarshalText(text []byte) error {
+	v, err := NewDateTime(string(text))
+	if err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+// Writes the value as element, or nothing if it is unset.
+func marshalElement(e *xml.Encoder, start xml.StartElement, zero bool, value string) error {
+	if zero {
+		return nil
+	}
+	return e.EncodeElement(value, start)
+}
+
+// Returns the value as attribute, or the zero attribute, which is omitted, if
+// it is unset.
+func marshalAttr(name xml.Name, zero bool, value string) (xml.Attr, error) {
+	if zero {
+		return xml.Attr{}, nil
+	}
+	return xml.Attr{Name: name, Value: value}, nil
+}
@@ -0,0 +1,82 @@
+package gha
+
+import (
+	"encoding/xml"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDateTimeMarshal(t *testing.T) {
+	type times struct {
+		XMLName  xml.Name `xml:"Times"`
+		Created  DateTime `xml:"created,attr"`
+		Checkin  Date     `xml:""`
+		Checkout Date     `xml:"checkout,attr"`
+		Deadline Time     `xml:""`
+	}
+
+	tests := []struct {
+		name  string
+		value times
+		want  string
+	}{
+		{
+			"All set",
+			times{
+				Created:  DateTime(time.Date(2017, 7, 18, 16, 20, 0, 0, time.FixedZone("", -4*60*60))),
+				Checkin:  newDate("2018-06-10"),
+				Checkout: newDate("2018-06-12"),
+				Deadline: newTime("00:00"),
+			},
+			`<Times created="2017-07-18T16:20:00-04:00" checkout="2018-06-12"><Checkin>2018-06-10</Checkin><Deadline>00:00</Deadline></Times>`,
+		},
+		{"None set", times{}, `<Times></Times>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := xml.Marshal(tt.value)
+			if err != nil {
+				t.Fatalf("Marshal data failed. %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Marshal got %s, want: %s", got, tt.want)
+			}
+
+			var v times
+			if err := xml.Unmarshal(got, &v); err != nil {
+				t.Fatalf("Unmarshal data failed. %v", err)
+			}
+			v.XMLName = tt.value.XMLName
+			if !reflect.DeepEqual(v, tt.value) {
+				printError(t, v, tt.value)
+			}
+		})
+	}
+}
+
+func TestDateTimeUnmarshalErrors(t *testing.T) {
+	for _, example := range []string{
+		`<Result><Checkin>10.06.2018</Checkin></Result>`,
+		`<Result><Refundable available="1" refundable_until_time="4pm"/></Result>`,
+		`<Transaction id="42" timestamp="2017-07-18 16:20:00"></Transaction>`,
+	} {
+		var v struct {
+			Result      *Result
+			Transaction *Transaction
+		}
+		if err := xml.Unmarshal([]byte("<v>"+example+"</v>"), &v); err == nil {
+			t.Errorf("Unmarshal of %s did not fail", example)
+		}
+	}
+}
+
+func TestTimeClock(t *testing.T) {
+	if h, m := newTime("18:30").Clock(); h != 18 || m != 30 {
+		t.Errorf("Clock got %v:%v, want: 18:30", h, m)
+	}
+	if h, m := (Time{}).Clock(); h != 0 || m != 0 {
+		t.Errorf("Clock of the zero value got %v:%v, want: 0:0", h, m)
+	}
+}
@@ -0,0 +1,122 @@
+package gha
+
+// Returns the rate with every pricing-related value it does not set taken from
+// the given parent, i.e. the <Result> or <RoomBundle> the rate belongs to.
+//
+// The rate_rule_id is not inherited. <Occupancy> and <OccupancyDetails> are
+// inherited together: if the rate sets one of them, it inherits neither.
+//
+// The returned rate does not share memory with r or parent.
+func (r Rate) Inherit(parent Rate) Rate {
+	e := Rate{
+		RateRuleID:            r.RateRuleID,
+		Baserate:              copyMoney(r.Baserate, parent.Baserate),
+		Tax:                   copyMoney(r.Tax, parent.Tax),
+		OtherFees:             copyMoney(r.OtherFees, parent.OtherFees),
+		Refundable:            parent.Refundable,
+		ChargeCurrency:        r.ChargeCurrency,
+		AllowablePointsOfSale: parent.AllowablePointsOfSale,
+		Occupancy:             parent.Occupancy,
+		OccupancyDetails:      parent.OccupancyDetails,
+		Custom1:               firstString(r.Custom1, parent.Custom1),
+		Custom2:               firstString(r.Custom2, parent.Custom2),
+		Custom3:               firstString(r.Custom3, parent.Custom3),
+		Custom4:               firstString(r.Custom4, parent.Custom4),
+		Custom5:               firstString(r.Custom5, parent.Custom5),
+	}
+
+	if e.ChargeCurrency == "" {
+		e.ChargeCurrency = parent.ChargeCurrency
+	}
+	if r.ExpirationTime != nil {
+		t := *r.ExpirationTime
+		e.ExpirationTime = &t
+	} else if parent.ExpirationTime != nil {
+		t := *parent.ExpirationTime
+		e.ExpirationTime = &t
+	}
+	if r.Refundable != nil {
+		e.Refundable = r.Refundable
+	}
+	if e.Refundable != nil {
+		refundable := *e.Refundable
+		e.Refundable = &refundable
+	}
+	if r.AllowablePointsOfSale != nil {
+		e.AllowablePointsOfSale = r.AllowablePointsOfSale
+	}
+	if e.AllowablePointsOfSale != nil {
+		e.AllowablePointsOfSale = &AllowablePointsOfSale{
+			PointOfSale: append([]PointOfSale(nil), e.AllowablePointsOfSale.PointOfSale...),
+		}
+	}
+	if r.Occupancy != 0 || r.OccupancyDetails != nil {
+		e.Occupancy, e.OccupancyDetails = r.Occupancy, r.OccupancyDetails
+	}
+	if e.OccupancyDetails != nil {
+		details := *e.OccupancyDetails
+		if details.Children != nil {
+			details.Children = &Children{
+				Child: append([]Child(nil), details.Children.Child...),
+			}
+		}
+		e.OccupancyDetails = &details
+	}
+
+	return e
+}
+
+// Returns a copy of the first of the given amounts that is set.
+func copyMoney(values ...*Money) *Money {
+	if m := firstMoney(values...); m != nil {
+		c := *m
+		return &c
+	}
+	return nil
+}
+
+// Returns the fully resolved rates of the result: the base rate of the
+// <Result>, if it has a <Baserate>, followed by one rate for each <Rate> in
+// <Rates> with all inherited values filled in. Room bundles are not included,
+// see RoomBundle.EffectiveRates.
+func (r *Result) EffectiveRates() []Rate {
+	return effectiveRates(r.Rate, r.Rates)
+}
+
+// Returns the fully resolved rates of the room bundle: the rate of the
+// <RoomBundle>, if it has a <Baserate>, followed by one rate for each <Rate>
+// in its <Rates> with all inherited values filled in.
+func (b *RoomBundle) EffectiveRates() []Rate {
+	return effectiveRates(b.Rate, b.Rates)
+}
+
+func effectiveRates(base Rate, rates *Rates) []Rate {
+	var all []Rate
+	if base.Baserate != nil {
+		all = append(all, base.Inherit(Rate{}))
+	}
+	if rates != nil {
+		for _, rate := range rates.Rate {
+			all = append(all, rate.Inherit(base))
+		}
+	}
+	return all
+}
+
+func firstString(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func firstMoney(values ...*Money) *Money {
+	for _, v := range values {
+		if v != nil {
+			return v
+		}
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package gha
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func readTransaction(t *testing.T, file string) Transaction {
+	request, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatalf("File reading error %v", err)
+	}
+
+	var tx Transaction
+	if err = xml.Unmarshal(request, &tx); err != nil {
+		t.Fatalf("Parsing request data failed with error: %v", err)
+	}
+	return tx
+}
+
+func TestResultEffectiveRates(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		want []Rate
+	}{
+		{
+			"Base rate and conditional rate",
+			"./testdata/Transaction-BaseRateAndConditionalRate.xml",
+			[]Rate{
+				{
+					Baserate:  money("200.00", "USD"),
+					Tax:       money("20.00", "USD"),
+					OtherFees: money("1.00", "USD"),
+				},
+				{
+					RateRuleID: "mobile",
+					Baserate:   money("180.00", "USD"),
+					Tax:        money("18.00", "USD"),
+					OtherFees:  money("1.00", "USD"),
+					Custom1:    "ratecode123",
+				},
+			},
+		},
+		{
+			"One itinerary pricing for one adult and one child",
+			"./testdata/Transaction-OneItineraryPricingForOneAdultChild.xml",
+			[]Rate{
+				{
+					Baserate:  money("62.18", "USD"),
+					Tax:       money("2.45", "USD"),
+					OtherFees: money("0.00", "USD"),
+				},
+				{
+					RateRuleID: "rule-951",
+					Occupancy:  2,
+					OccupancyDetails: &OccupancyDetails{
+						NumAdults: 1,
+						Children:  &Children{Child: []Child{{17}}},
+					},
+					Baserate:  money("42.61", "USD"),
+					Tax:       money("5.70", "USD"),
+					OtherFees: money("0.00", "USD"),
+					Custom1:   "abc4",
+					AllowablePointsOfSale: &AllowablePointsOfSale{
+						PointOfSale: []PointOfSale{{"yourhotelpartnersite.com"}},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := readTransaction(t, tt.file)
+			if got := tx.Result[0].EffectiveRates(); !reflect.DeepEqual(got, tt.want) {
+				printError(t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResultEffectiveRatesOccupancy(t *testing.T) {
+	tx := readTransaction(t, "./testdata/Transaction-MultiRateExample.xml")
+
+	rates := tx.Result[0].EffectiveRates()
+	if len(rates) != 6 {
+		t.Fatalf("len(EffectiveRates) got %v, want: 6", len(rates))
+	}
+	for i, want := range []uint8{2, 1, 3, 4, 5, 6} {
+		if rates[i].Occupancy != want {
+			t.Errorf("Occupancy of rate %d got %v, want: %v", i, rates[i].Occupancy, want)
+		}
+	}
+}
+
+func TestRoomBundleEffectiveRates(t *testing.T) {
+	tx := readTransaction(t, "./testdata/Transaction-RoomBundle.xml")
+
+	got := tx.Result[0].RoomBundle[0].EffectiveRates()
+	want := []Rate{
+		{
+			Baserate:  money("240.00", "USD"),
+			Tax:       money("24.00", "USD"),
+			OtherFees: money("1.00", "USD"),
+			Occupancy: 2,
+		},
+		{
+			RateRuleID: "mobile",
+			Baserate:   money("220.00", "USD"),
+			Tax:        money("22.00", "USD"),
+			OtherFees:  money("1.00", "USD"),
+			Occupancy:  2,
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		printError(t, got, want)
+	}
+}
+
+func TestRateInheritDoesNotShareMemory(t *testing.T) {
+	parent := Rate{
+		OtherFees:  money("1.00", "USD"),
+		Refundable: &Refundable{Available: true, RefundableUntilDays: 3},
+		OccupancyDetails: &OccupancyDetails{
+			NumAdults: 2,
+			Children:  &Children{Child: []Child{{4}}},
+		},
+	}
+
+	e := Rate{Baserate: money("180.00", "USD")}.Inherit(parent)
+	e.OtherFees.Value = MustParseDecimal("5.00")
+	e.Refundable.RefundableUntilDays = 7
+	e.OccupancyDetails.Children.Child[0].Age = 10
+
+	if parent.OtherFees.Value.String() != "1.00" ||
+		parent.Refundable.RefundableUntilDays != 3 ||
+		parent.OccupancyDetails.Children.Child[0].Age != 4 {
+		t.Errorf("Modifying the effective rate changed the parent: %+v", parent)
+	}
+}
@@ -0,0 +1,187 @@
+package gha
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"time"
+)
+
+// Reads a single XML document into v.
+func decodeFile(r io.Reader, v interface{}) error {
+	return xml.NewDecoder(r).Decode(v)
+}
+
+// Writes v as indented XML document, including the XML declaration and a
+// trailing newline.
+func encodeFile(w io.Writer, v interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// Max. size of a Transaction message in bytes.
+const MaxTransactionSize = 100 << 20
+
+// Error returned if a single element does not fit into a Transaction message.
+var ErrElementTooLarge = errors.New("gha: element exceeds the max. size of a Transaction message")
+
+const transactionEnd = "</Transaction>"
+
+// Writes <PropertyDataSet> and <Result> elements one at a time into
+// Transaction messages, without holding the whole message in memory.
+//
+// When the next element would exceed the max. message size, the current
+// message is completed and a new Transaction with a fresh ID and timestamp
+// is started on the next writer.
+type TransactionEncoder struct {
+	// Max. size of a message in bytes. Defaults to MaxTransactionSize.
+	MaxSize int64
+
+	// Partner key that is set on every Transaction, optional.
+	Partner string
+
+	// Returns the ID of a new Transaction. Defaults to NewTransactionID.
+	NewID func() string
+
+	// Returns the timestamp of a new Transaction. Defaults to time.Now.
+	Now func() time.Time
+
+	next     func() (io.Writer, error)
+	w        io.Writer
+	size     int64
+	messages int
+}
+
+// Returns a new TransactionEncoder. The next function is called for every
+// message to get the writer it is written to. If the writer implements
+// io.Closer, it is closed when the message is complete.
+func NewTransactionEncoder(next func() (io.Writer, error)) *TransactionEncoder {
+	return &TransactionEncoder{next: next}
+}
+
+// Writes the given <Result> element.
+func (e *TransactionEncoder) EncodeResult(r *Result) error {
+	return e.encode(r)
+}
+
+// Writes the given <PropertyDataSet> element.
+func (e *TransactionEncoder) EncodePropertyDataSet(p *PropertyDataSet) error {
+	return e.encode(p)
+}
+
+// Returns the number of messages started so far.
+func (e *TransactionEncoder) Messages() int {
+	return e.messages
+}
+
+// Completes the current message. The next element is written to a new
+// message. Close must be called after the last element.
+func (e *TransactionEncoder) Close() error {
+	if e.w == nil {
+		return nil
+	}
+
+	w := e.w
+	e.w, e.size = nil, 0
+	if _, err := io.WriteString(w, transactionEnd); err != nil {
+		return err
+	}
+	if c, ok := w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+func (e *TransactionEncoder) encode(v interface{}) error {
+	data, err := xml.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	maxSize := e.MaxSize
+	if maxSize <= 0 {
+		maxSize = MaxTransactionSize
+	}
+	fits := func(size int64) bool {
+		return size+int64(len(data))+int64(len(transactionEnd)) <= maxSize
+	}
+
+	if e.w != nil && !fits(e.size) {
+		if err := e.Close(); err != nil {
+			return err
+		}
+	}
+	if e.w == nil {
+		header, err := e.header()
+		if err != nil {
+			return err
+		}
+		if !fits(int64(len(header))) {
+			return ErrElementTooLarge
+		}
+		if err := e.start(header); err != nil {
+			return err
+		}
+	}
+
+	n, err := e.w.Write(data)
+	e.size += int64(n)
+	return err
+}
+
+// Returns the XML declaration and the start element of a new message.
+func (e *TransactionEncoder) header() ([]byte, error) {
+	newID, now := e.NewID, e.Now
+	if newID == nil {
+		newID = NewTransactionID
+	}
+	if now == nil {
+		now = time.Now
+	}
+
+	timestamp, err := DateTime(now().Truncate(time.Second)).MarshalXMLAttr(xml.Name{Local: "timestamp"})
+	if err != nil {
+		return nil, err
+	}
+	start := xml.StartElement{
+		Name: xml.Name{Local: "Transaction"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "id"}, Value: newID()}, timestamp},
+	}
+	if e.Partner != "" {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "partner"}, Value: e.Partner})
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	if err := enc.EncodeToken(start); err != nil {
+		return nil, err
+	}
+	if err := enc.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Starts a new message with the given header on the next writer.
+func (e *TransactionEncoder) start(header []byte) error {
+	w, err := e.next()
+	if err != nil {
+		return err
+	}
+	e.w, e.size = w, 0
+	e.messages++
+
+	n, err := w.Write(header)
+	e.size += int64(n)
+	return err
+}
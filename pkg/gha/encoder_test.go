@@ -0,0 +1,120 @@
+package gha
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Buffer that records whether it has been closed.
+type closeBuffer struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (b *closeBuffer) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestTransactionEncoder(t *testing.T) {
+	var messages []*closeBuffer
+	enc := NewTransactionEncoder(func() (io.Writer, error) {
+		b := &closeBuffer{}
+		messages = append(messages, b)
+		return b, nil
+	})
+	enc.MaxSize = 1024
+	enc.Partner = "partner"
+	ids := 0
+	enc.NewID = func() string {
+		ids++
+		return fmt.Sprintf("tx%d", ids)
+	}
+	enc.Now = func() time.Time { return time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC) }
+
+	checkin, _ := NewDate("2018-06-10")
+	if err := enc.EncodePropertyDataSet(&PropertyDataSet{
+		Property: Property{"0"},
+		RoomData: []RoomData{{RoomID: "RoomType101"}},
+	}); err != nil {
+		t.Fatalf("EncodePropertyDataSet failed with error: %v", err)
+	}
+	for i := 0; i < 20; i++ {
+		r := Result{
+			Property: Property{fmt.Sprint(i)},
+			Checkin:  checkin,
+			Nights:   2,
+			Rate: Rate{
+				Baserate: money("278.33", "USD"),
+				Tax:      money("25.12", "USD"),
+			},
+		}
+		if err := enc.EncodeResult(&r); err != nil {
+			t.Fatalf("EncodeResult failed with error: %v", err)
+		}
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatalf("Close failed with error: %v", err)
+	}
+
+	if len(messages) < 2 || enc.Messages() != len(messages) {
+		t.Fatalf("Messages got %v (%v writers), want more than one", enc.Messages(), len(messages))
+	}
+
+	results := 0
+	for i, m := range messages {
+		if !m.closed {
+			t.Errorf("Message %d has not been closed", i)
+		}
+		if m.Len() > 1024 {
+			t.Errorf("Message %d has %d bytes, want at most 1024", i, m.Len())
+		}
+		if !strings.HasPrefix(m.String(), xml.Header) {
+			t.Errorf("Message %d does not start with the XML header", i)
+		}
+
+		var tx Transaction
+		if err := xml.Unmarshal(m.Bytes(), &tx); err != nil {
+			t.Fatalf("Parsing message %d failed with error: %v", i, err)
+		}
+		if want := fmt.Sprintf("tx%d", i+1); tx.ID != want {
+			t.Errorf("ID got %v, want: %v", tx.ID, want)
+		}
+		if tx.Partner != "partner" {
+			t.Errorf("Partner got %v, want: partner", tx.Partner)
+		}
+		if err := tx.Validate(); err != nil {
+			t.Errorf("Message %d is invalid: %v", i, err)
+		}
+		for _, r := range tx.Result {
+			if r.Property.ID != fmt.Sprint(results) {
+				t.Errorf("Property got %v, want: %v", r.Property.ID, results)
+			}
+			results++
+		}
+	}
+	if results != 20 {
+		t.Errorf("Results got %v, want: 20", results)
+	}
+}
+
+func TestTransactionEncoderElementTooLarge(t *testing.T) {
+	enc := NewTransactionEncoder(func() (io.Writer, error) {
+		t.Errorf("Message started for an element that does not fit")
+		return &bytes.Buffer{}, nil
+	})
+	enc.MaxSize = 200
+
+	r := Result{Property: Property{strings.Repeat("x", 200)}}
+	if err := enc.EncodeResult(&r); err != ErrElementTooLarge {
+		t.Errorf("EncodeResult got %v, want: %v", err, ErrElementTooLarge)
+	}
+	if enc.Messages() != 0 {
+		t.Errorf("Messages got %v, want: 0", enc.Messages())
+	}
+}
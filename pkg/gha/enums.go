@@ -0,0 +1,118 @@
+package gha
+
+import "fmt"
+
+// Specifies when and how the user pays for a rate.
+type ChargeCurrency string
+
+const (
+	// The user pays a deposit when booking and the rest at the hotel.
+	ChargeCurrencyDeposit ChargeCurrency = "deposit"
+
+	// The user pays at the hotel, in the currency of the hotel.
+	ChargeCurrencyHotel ChargeCurrency = "hotel"
+
+	// The user pays in installments.
+	ChargeCurrencyInstallment ChargeCurrency = "installment"
+
+	// The user pays when booking, in the currency of the rate.
+	ChargeCurrencyWeb ChargeCurrency = "web"
+)
+
+// Returns true if c is one of the ChargeCurrency constants.
+func (c ChargeCurrency) Valid() bool {
+	switch c {
+	case ChargeCurrencyDeposit, ChargeCurrencyHotel, ChargeCurrencyInstallment, ChargeCurrencyWeb:
+		return true
+	}
+	return false
+}
+
+func (c ChargeCurrency) MarshalText() ([]byte, error) {
+	return marshalEnum("charge currency", string(c), c.Valid())
+}
+
+func (c *ChargeCurrency) UnmarshalText(text []byte) error {
+	v := ChargeCurrency(text)
+	if !v.Valid() {
+		return unknownEnum("charge currency", v)
+	}
+	*c = v
+	return nil
+}
+
+// Type of the device a user searches on.
+type DeviceType string
+
+const (
+	DeviceDesktop DeviceType = "desktop"
+	DeviceMobile  DeviceType = "mobile"
+	DeviceTablet  DeviceType = "tablet"
+)
+
+// Returns true if d is one of the DeviceType constants.
+func (d DeviceType) Valid() bool {
+	switch d {
+	case DeviceDesktop, DeviceMobile, DeviceTablet:
+		return true
+	}
+	return false
+}
+
+func (d DeviceType) MarshalText() ([]byte, error) {
+	return marshalEnum("device type", string(d), d.Valid())
+}
+
+func (d *DeviceType) UnmarshalText(text []byte) error {
+	v := DeviceType(text)
+	if !v.Valid() {
+		return unknownEnum("device type", v)
+	}
+	*d = v
+	return nil
+}
+
+// Specifies how the properties of a Hint Response may share an <Item>, see
+// Property.
+type MultipleItineraries string
+
+const (
+	// Check-in range items may list more than one property.
+	MultipleItinerariesCheckinRange MultipleItineraries = "checkin_range"
+
+	// Ranged stay items may list more than one property.
+	MultipleItinerariesAffectedDates MultipleItineraries = "affected_dates"
+)
+
+// Returns true if m is one of the MultipleItineraries constants.
+func (m MultipleItineraries) Valid() bool {
+	switch m {
+	case MultipleItinerariesCheckinRange, MultipleItinerariesAffectedDates:
+		return true
+	}
+	return false
+}
+
+func (m MultipleItineraries) MarshalText() ([]byte, error) {
+	return marshalEnum("multiple itineraries", string(m), m.Valid())
+}
+
+func (m *MultipleItineraries) UnmarshalText(text []byte) error {
+	v := MultipleItineraries(text)
+	if !v.Valid() {
+		return unknownEnum("multiple itineraries", v)
+	}
+	*m = v
+	return nil
+}
+
+func marshalEnum(kind, value string, valid bool) ([]byte, error) {
+	if !valid {
+		return nil, unknownEnum(kind, value)
+	}
+	return []byte(value), nil
+}
+
+func unknownEnum(kind string, value interface{}) error {
+	return fmt.Errorf("gha: unknown %s %q", kind, value)
+}
@@ -0,0 +1,85 @@
+package gha
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+func TestEnumsMarshal(t *testing.T) {
+	type enums struct {
+		XMLName             xml.Name            `xml:"Enums"`
+		Device              DeviceType          `xml:"device,attr,omitempty"`
+		ChargeCurrency      ChargeCurrency      `xml:",omitempty"`
+		MultipleItineraries MultipleItineraries `xml:",omitempty"`
+	}
+
+	tests := []struct {
+		name  string
+		value enums
+		want  string
+	}{
+		{
+			"All set",
+			enums{
+				Device:              DeviceTablet,
+				ChargeCurrency:      ChargeCurrencyHotel,
+				MultipleItineraries: MultipleItinerariesAffectedDates,
+			},
+			`<Enums device="tablet"><ChargeCurrency>hotel</ChargeCurrency><MultipleItineraries>affected_dates</MultipleItineraries></Enums>`,
+		},
+		{"None set", enums{}, `<Enums></Enums>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := xml.Marshal(tt.value)
+			if err != nil {
+				t.Fatalf("Marshal data failed. %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Marshal got %s, want: %s", got, tt.want)
+			}
+
+			var e enums
+			if err := xml.Unmarshal(got, &e); err != nil {
+				t.Fatalf("Unmarshal data failed. %v", err)
+			}
+			e.XMLName = tt.value.XMLName
+			if e != tt.value {
+				t.Errorf("Unmarshal got %+v, want: %+v", e, tt.value)
+			}
+		})
+	}
+}
+
+func TestEnumsRejectUnknownValues(t *testing.T) {
+	for _, v := range []interface{}{
+		&Rate{ChargeCurrency: "card"},
+		&Context{UserDevice: "phone"},
+		&Match{Status: "yes", Device: "Mobile"},
+	} {
+		if _, err := xml.Marshal(v); err == nil {
+			t.Errorf("Marshal of %+v did not fail", v)
+		}
+	}
+
+	for _, data := range []string{
+		`<Result><ChargeCurrency>card</ChargeCurrency></Result>`,
+		`<Result><ChargeCurrency></ChargeCurrency></Result>`,
+	} {
+		var r Result
+		if err := xml.Unmarshal([]byte(data), &r); err == nil {
+			t.Errorf("Unmarshal of %s did not fail", data)
+		}
+	}
+
+	var q Query
+	if err := xml.Unmarshal([]byte(`<Query><Context><UserDevice>phone</UserDevice></Context></Query>`), &q); err == nil {
+		t.Errorf("Unmarshal of an unknown device type did not fail")
+	}
+
+	var m MultipleItineraries
+	if err := m.UnmarshalText([]byte("checkin-range")); err == nil {
+		t.Errorf("UnmarshalText of an unknown value did not fail")
+	}
+}
@@ -2,7 +2,4 @@ module github.com/f-go/link/pkg/gha
 
 go 1.14
 
-require (
-	github.com/f-go/go-custom-datetime v0.2.0
-	github.com/sergi/go-diff v1.1.0
-)
+require github.com/sergi/go-diff v1.1.0
@@ -0,0 +1,144 @@
+package gha
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// Element of a canonical XML document.
+type node struct {
+	name     string
+	attr     []xml.Attr
+	text     string
+	children []*node
+}
+
+// Returns the given XML document in canonical form: comments, processing
+// instructions and whitespace between elements are dropped and attributes
+// are sorted by name. Elements and values are kept as they are.
+func canonicalize(data []byte) (string, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	root := &node{}
+	stack := []*node{root}
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		top := stack[len(stack)-1]
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			n := &node{name: tok.Name.Local}
+			for _, a := range tok.Attr {
+				if a.Name.Space == "xmlns" || a.Name.Local == "xmlns" || a.Name.Space == "http://www.w3.org/2001/XMLSchema-instance" {
+					continue
+				}
+				n.attr = append(n.attr, xml.Attr{Name: xml.Name{Local: a.Name.Local}, Value: a.Value})
+			}
+			sort.Slice(n.attr, func(i, j int) bool {
+				return n.attr[i].Name.Local < n.attr[j].Name.Local
+			})
+			top.children = append(top.children, n)
+			stack = append(stack, n)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			top.text += string(tok)
+		}
+	}
+
+	var buf strings.Builder
+	var write func(n *node)
+	write = func(n *node) {
+		buf.WriteString("<" + n.name)
+		for _, a := range n.attr {
+			buf.WriteString(" " + a.Name.Local + `="` + a.Value + `"`)
+		}
+		buf.WriteString(">")
+		if len(n.children) == 0 {
+			xml.EscapeText(&buf, []byte(strings.TrimSpace(n.text)))
+		}
+		for _, c := range n.children {
+			buf.WriteString("\n")
+			write(c)
+		}
+		buf.WriteString("</" + n.name + ">")
+	}
+	for _, n := range root.children {
+		write(n)
+	}
+	return buf.String(), nil
+}
+
+func TestMarshalGolden(t *testing.T) {
+	files, err := filepath.Glob("./testdata/*.xml")
+	if err != nil {
+		t.Fatalf("Listing test data failed with error: %v", err)
+	}
+
+	for _, file := range files {
+		name := filepath.Base(file)
+		t.Run(name, func(t *testing.T) {
+			data, err := ioutil.ReadFile(file)
+			if err != nil {
+				t.Fatalf("File reading error %v", err)
+			}
+
+			var v interface{}
+			switch prefix := strings.SplitN(name, "-", 2)[0]; strings.TrimSuffix(prefix, ".xml") {
+			case "Transaction":
+				v = &Transaction{}
+			case "Query":
+				v = &Query{}
+			case "QueryControl":
+				v = &QueryControl{}
+			case "Hint":
+				v = &Hint{}
+			case "HintRequest":
+				v = &HintRequest{}
+			case "HotelList":
+				v = &Listings{}
+			case "PointsOfSale":
+				v = &PointsOfSale{}
+			case "RateRules":
+				v = &RateRuleDefinitions{}
+			case "Promotions":
+				v = &Promotions{}
+			case "TaxFeeInfo":
+				v = &TaxFeeInfo{}
+			default:
+				t.Fatalf("No message type for %s", name)
+			}
+			if err := xml.Unmarshal(data, v); err != nil {
+				t.Fatalf("Parsing file failed with error: %v", err)
+			}
+
+			got, err := xml.Marshal(v)
+			if err != nil {
+				t.Fatalf("Marshal data failed. %v", err)
+			}
+
+			want, err := canonicalize(data)
+			if err != nil {
+				t.Fatalf("Canonicalizing file failed with error: %v", err)
+			}
+			canonical, err := canonicalize(got)
+			if err != nil {
+				t.Fatalf("Canonicalizing output failed with error: %v", err)
+			}
+			if canonical != want {
+				printError(t, canonical, want)
+			}
+		})
+	}
+}
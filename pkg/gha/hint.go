@@ -1,15 +1,11 @@
 package gha
 
-import (
-	cdt "github.com/f-go/go-custom-datetime"
-)
-
 // Defines a Hint Request message that contains the time Google last
 // received an update from your server.
 type HintRequest struct {
-	ID            string             `xml:"id,attr"`
-	Timestamp     cdt.CustomDateTime `xml:"timestamp,attr"`
-	LastFetchTime cdt.CustomDateTime `xml:""`
+	ID            string   `xml:"id,attr"`
+	Timestamp     DateTime `xml:"timestamp,attr"`
+	LastFetchTime DateTime `xml:""`
 }
 
 // Hint Response message that specifies the hotels whose prices have
@@ -22,8 +18,8 @@ type Hint struct {
 // A container for the hotel/itinerary to be updated.
 type Item struct {
 	Property            []Property           `xml:""`
-	FirstDate           cdt.CustomDate       `xml:",omitempty"`
-	LastDate            cdt.CustomDate       `xml:",omitempty"`
+	FirstDate           Date                 `xml:",omitempty"`
+	LastDate            Date                 `xml:",omitempty"`
 	Stay                *Stay                `xml:",omitempty"`
 	StaysIncludingRange *StaysIncludingRange `xml:",omitempty"`
 }
@@ -31,13 +27,52 @@ type Item struct {
 // A container for the checkin date and length of stay elements in  an exact
 // itinerary Hint Response message. Each <Item> can contain only a single <Stay>.
 type Stay struct {
-	CheckInDate  cdt.CustomDate `xml:""`
-	LengthOfStay int8           `xml:""`
+	CheckInDate  Date `xml:""`
+	LengthOfStay int8 `xml:""`
 }
 
 // A container for the first date and last date elements in a ranged stay
 // Hint Response message.
 type StaysIncludingRange struct {
-	FirstDate cdt.CustomDate `xml:""`
-	LastDate  cdt.CustomDate `xml:""`
+	FirstDate Date `xml:""`
+	LastDate  Date `xml:""`
+}
+
+// Max. number of properties in an exact itinerary <Item>.
+const MaxExactItemProperties = 100
+
+// Returns an exact itinerary <Item>: Google fetches prices for the given
+// check-in date and length of stay of all given properties.
+func NewExactItem(checkin Date, lengthOfStay int8, properties ...Property) Item {
+	return Item{
+		Property: properties,
+		Stay: &Stay{
+			CheckInDate:  checkin,
+			LengthOfStay: lengthOfStay,
+		},
+	}
+}
+
+// Returns a check-in range <Item>: Google fetches prices for all itineraries
+// with a check-in date between first and last, inclusive.
+func NewCheckinRangeItem(first, last Date, properties ...Property) Item {
+	return Item{
+		Property:  properties,
+		FirstDate: first,
+		LastDate:  last,
+	}
+}
+
+// Returns a ranged stay <Item>: Google fetches prices for all itineraries that
+// include a night between first and last, inclusive. Set last to first for a
+// single night.
+func NewRangedStayItem(first, last Date, properties ...Property) Item {
+	r := &StaysIncludingRange{FirstDate: first}
+	if last != first {
+		r.LastDate = last
+	}
+	return Item{
+		Property:            properties,
+		StaysIncludingRange: r,
+	}
 }
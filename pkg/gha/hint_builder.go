@@ -0,0 +1,327 @@
+package gha
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Builds a Hint Response from price changes of single itineraries and of
+// stay dates. The changes are grouped into as few <Item> elements as
+// possible without breaking the limits of Google and of the query control
+// options:
+// - A changed itinerary is sent as exact itinerary. Up to
+//   MaxExactItemProperties properties with the same itinerary share an item.
+// - Several changed itineraries of a property with adjacent check-in dates
+//   are sent as check-in range, unless exact itineraries shared with other
+//   properties need fewer items.
+// - Changed stay dates are sent as ranged stay. Overlapping and adjacent
+//   ranges of a property are merged.
+//
+// The zero value is an empty builder with the default query control options.
+type HintBuilder struct {
+	// Query control options of the partner account, optional.
+	Control *QueryControl
+
+	itineraries map[string]map[itinerary]bool
+	stays       map[string][]dateRange
+}
+
+// Returns a new, empty HintBuilder that respects the given options, which
+// can be nil.
+func NewHintBuilder(control *QueryControl) *HintBuilder {
+	return &HintBuilder{Control: control}
+}
+
+// An exact itinerary.
+type itinerary struct {
+	checkin time.Time
+	nights  int
+}
+
+// A range of dates, inclusive.
+type dateRange struct {
+	first, last time.Time
+}
+
+const day = 24 * time.Hour
+
+// Adds a price change of the itinerary with the given check-in date and
+// length of stay. Lengths of stay below 1 are ignored; lengths of stay that
+// cannot be sent in a <Stay> are added as stay dates, see AddStayDates.
+func (b *HintBuilder) AddItinerary(property Property, checkin Date, lengthOfStay int) {
+	if lengthOfStay < 1 {
+		return
+	}
+	if lengthOfStay > math.MaxInt8 {
+		last := truncateDate(checkin).AddDate(0, 0, lengthOfStay-1)
+		b.AddStayDates(property, checkin, Date(last))
+		return
+	}
+	if b.itineraries == nil {
+		b.itineraries = map[string]map[itinerary]bool{}
+	}
+	if b.itineraries[property.ID] == nil {
+		b.itineraries[property.ID] = map[itinerary]bool{}
+	}
+	b.itineraries[property.ID][itinerary{truncateDate(checkin), lengthOfStay}] = true
+}
+
+// Adds a price change of all stays that include a night between first and
+// last, inclusive.
+func (b *HintBuilder) AddStayDates(property Property, first, last Date) {
+	r := dateRange{truncateDate(first), truncateDate(last)}
+	if r.last.Before(r.first) {
+		r.first, r.last = r.last, r.first
+	}
+	if b.stays == nil {
+		b.stays = map[string][]dateRange{}
+	}
+	b.stays[property.ID] = append(b.stays[property.ID], r)
+}
+
+// Returns the Hint Response for all changes added so far: exact itineraries
+// first, then check-in ranges and ranged stays, each ordered by date.
+func (b *HintBuilder) Hint() Hint {
+	properties := make([]string, 0, len(b.itineraries))
+	for p := range b.itineraries {
+		properties = append(properties, p)
+	}
+	sort.Strings(properties)
+
+	var choices []runChoice
+	for _, p := range properties {
+		for _, run := range checkinRuns(b.itineraries[p], b.Control.CheckinRangeDays()) {
+			choices = append(choices, runChoice{property: p, run: run})
+		}
+	}
+	b.chooseShapes(choices)
+	exact, checkinRanges := groupRuns(choices)
+
+	properties = properties[:0]
+	for p := range b.stays {
+		properties = append(properties, p)
+	}
+	sort.Strings(properties)
+
+	rangedStays := map[dateRange][]Property{}
+	for _, p := range properties {
+		for _, r := range mergeRanges(b.stays[p]) {
+			rangedStays[r] = append(rangedStays[r], Property{p})
+		}
+	}
+
+	hint := Hint{Item: []Item{}}
+	for _, it := range sortedItineraries(exact) {
+		for _, ps := range chunk(exact[it], b.Control.ExactItemProperties()) {
+			hint.Item = append(hint.Item, NewExactItem(
+				Date(it.checkin), int8(it.nights), ps...))
+		}
+	}
+	for _, r := range sortedRanges(checkinRanges) {
+		for _, ps := range chunk(checkinRanges[r], b.Control.CheckinRangeItemProperties()) {
+			hint.Item = append(hint.Item, NewCheckinRangeItem(
+				Date(r.first), Date(r.last), ps...))
+		}
+	}
+	for _, r := range sortedRanges(rangedStays) {
+		for _, ps := range chunk(rangedStays[r], b.Control.RangedStayItemProperties()) {
+			hint.Item = append(hint.Item, NewRangedStayItem(
+				Date(r.first), Date(r.last), ps...))
+		}
+	}
+	return hint
+}
+
+// A run of itineraries of a property and whether it is sent as check-in
+// range or as exact itineraries.
+type runChoice struct {
+	property string
+	run      checkinRun
+	asRange  bool
+}
+
+// Sets the shape of the runs that needs the fewest items. Starting with
+// exact itineraries only, it switches single runs and all runs with the same
+// dates between both shapes as long as that saves items, so exact
+// itineraries are kept if both shapes need the same number of items. Runs
+// with a single itinerary are always sent as exact itinerary.
+func (b *HintBuilder) chooseShapes(choices []runChoice) {
+	var moves [][]int
+	byDates := map[dateRange][]int{}
+	for i, c := range choices {
+		if len(c.run) > 1 {
+			moves = append(moves, []int{i})
+			byDates[c.run.dates()] = append(byDates[c.run.dates()], i)
+		}
+	}
+	dates := make([]dateRange, 0, len(byDates))
+	for r := range byDates {
+		dates = append(dates, r)
+	}
+	sortDateRanges(dates)
+	for _, r := range dates {
+		if len(byDates[r]) > 1 {
+			moves = append(moves, byDates[r])
+		}
+	}
+
+	best := b.itemCount(choices)
+	for improved := true; improved; {
+		improved = false
+		for _, move := range moves {
+			for _, asRange := range []bool{true, false} {
+				old := make([]bool, len(move))
+				for j, i := range move {
+					old[j], choices[i].asRange = choices[i].asRange, asRange
+				}
+				if n := b.itemCount(choices); n < best {
+					best, improved = n, true
+					continue
+				}
+				for j, i := range move {
+					choices[i].asRange = old[j]
+				}
+			}
+		}
+	}
+}
+
+// Returns the number of items the runs need in their current shape.
+func (b *HintBuilder) itemCount(choices []runChoice) int {
+	exact, checkinRanges := groupRuns(choices)
+	n := 0
+	for _, ps := range exact {
+		n += chunkCount(len(ps), b.Control.ExactItemProperties())
+	}
+	for _, ps := range checkinRanges {
+		n += chunkCount(len(ps), b.Control.CheckinRangeItemProperties())
+	}
+	return n
+}
+
+// Returns the properties of the runs by exact itinerary and by check-in
+// range, in the order of the runs.
+func groupRuns(choices []runChoice) (map[itinerary][]Property, map[dateRange][]Property) {
+	exact := map[itinerary][]Property{}
+	checkinRanges := map[dateRange][]Property{}
+	for _, c := range choices {
+		if c.asRange {
+			r := c.run.dates()
+			checkinRanges[r] = append(checkinRanges[r], Property{c.property})
+			continue
+		}
+		for _, it := range c.run {
+			exact[it] = append(exact[it], Property{c.property})
+		}
+	}
+	return exact, checkinRanges
+}
+
+// Returns the number of chunks of at most limit properties that n
+// properties are split into, see chunk.
+func chunkCount(n, limit int) int {
+	return (n + limit - 1) / limit
+}
+
+// Splits the given properties into chunks of at most n properties.
+func chunk(properties []Property, n int) [][]Property {
+	var chunks [][]Property
+	for len(properties) > n {
+		chunks = append(chunks, properties[:n])
+		properties = properties[n:]
+	}
+	return append(chunks, properties)
+}
+
+// Itineraries of a property with adjacent check-in dates, sorted.
+type checkinRun []itinerary
+
+// Returns the first and the last check-in date of the run.
+func (r checkinRun) dates() dateRange {
+	return dateRange{r[0].checkin, r[len(r)-1].checkin}
+}
+
+// Returns the given itineraries sorted and split into runs of adjacent
+// check-in dates. A run has at most maxDays check-in dates, unless maxDays
+// is 0.
+func checkinRuns(set map[itinerary]bool, maxDays int) []checkinRun {
+	its := make([]itinerary, 0, len(set))
+	for it := range set {
+		its = append(its, it)
+	}
+	sortItineraries(its)
+
+	var runs []checkinRun
+	for i, it := range its {
+		if i == 0 || it.checkin.Sub(its[i-1].checkin) > day {
+			runs = append(runs, nil)
+		} else if r := runs[len(runs)-1]; maxDays > 0 && !it.checkin.Equal(r[len(r)-1].checkin) &&
+			it.checkin.Sub(r[0].checkin) >= time.Duration(maxDays)*day {
+			runs = append(runs, nil)
+		}
+		runs[len(runs)-1] = append(runs[len(runs)-1], it)
+	}
+	return runs
+}
+
+// Returns the given ranges sorted, with overlapping and adjacent ranges merged.
+func mergeRanges(ranges []dateRange) []dateRange {
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].first.Before(ranges[j].first)
+	})
+
+	var merged []dateRange
+	for _, r := range ranges {
+		if n := len(merged); n > 0 && !r.first.After(merged[n-1].last.Add(day)) {
+			if r.last.After(merged[n-1].last) {
+				merged[n-1].last = r.last
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+func sortItineraries(its []itinerary) {
+	sort.Slice(its, func(i, j int) bool {
+		if !its[i].checkin.Equal(its[j].checkin) {
+			return its[i].checkin.Before(its[j].checkin)
+		}
+		return its[i].nights < its[j].nights
+	})
+}
+
+func sortedItineraries(m map[itinerary][]Property) []itinerary {
+	its := make([]itinerary, 0, len(m))
+	for it := range m {
+		its = append(its, it)
+	}
+	sortItineraries(its)
+	return its
+}
+
+func sortedRanges(m map[dateRange][]Property) []dateRange {
+	ranges := make([]dateRange, 0, len(m))
+	for r := range m {
+		ranges = append(ranges, r)
+	}
+	sortDateRanges(ranges)
+	return ranges
+}
+
+func sortDateRanges(ranges []dateRange) {
+	sort.Slice(ranges, func(i, j int) bool {
+		if !ranges[i].first.Equal(ranges[j].first) {
+			return ranges[i].first.Before(ranges[j].first)
+		}
+		return ranges[i].last.Before(ranges[j].last)
+	})
+}
+
+// Returns the date of the given day at midnight UTC.
+func truncateDate(d Date) time.Time {
+	t := time.Time(d)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
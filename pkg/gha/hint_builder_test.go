@@ -0,0 +1,219 @@
+package gha
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func properties(ids ...string) []Property {
+	var ps []Property
+	for _, id := range ids {
+		ps = append(ps, Property{id})
+	}
+	return ps
+}
+
+func TestHintBuilder(t *testing.T) {
+	type change struct {
+		property    string
+		first, last string
+		nights      int
+	}
+	changes := []change{
+		{"1", "2018-07-01", "", 1},
+		{"1", "2018-07-02", "", 1},
+		{"1", "2018-07-03", "", 1},
+		{"2", "2018-07-01", "", 2},
+		{"2", "2018-07-02", "", 2},
+		{"2", "2018-07-03", "", 2},
+		{"1", "2018-08-01", "2018-08-02", 0},
+		{"2", "2018-08-02", "2018-08-01", 0},
+		{"3", "2018-08-01", "2018-08-02", 0},
+	}
+
+	tests := []struct {
+		name    string
+		control *QueryControl
+		want    []Item
+	}{
+		{
+			"Defaults",
+			nil,
+			[]Item{
+				NewCheckinRangeItem(newDate("2018-07-01"), newDate("2018-07-03"), properties("1")...),
+				NewCheckinRangeItem(newDate("2018-07-01"), newDate("2018-07-03"), properties("2")...),
+				NewRangedStayItem(newDate("2018-08-01"), newDate("2018-08-02"), properties("1")...),
+				NewRangedStayItem(newDate("2018-08-01"), newDate("2018-08-02"), properties("2")...),
+				NewRangedStayItem(newDate("2018-08-01"), newDate("2018-08-02"), properties("3")...),
+			},
+		},
+		{
+			"Check-in ranges with multiple properties and max. 2 days",
+			&QueryControl{MultipleItineraries: MultipleItinerariesCheckinRange, MaxItineraries: 2},
+			[]Item{
+				NewExactItem(newDate("2018-07-03"), 1, properties("1")...),
+				NewExactItem(newDate("2018-07-03"), 2, properties("2")...),
+				NewCheckinRangeItem(newDate("2018-07-01"), newDate("2018-07-02"), properties("1", "2")...),
+				NewRangedStayItem(newDate("2018-08-01"), newDate("2018-08-02"), properties("1")...),
+				NewRangedStayItem(newDate("2018-08-01"), newDate("2018-08-02"), properties("2")...),
+				NewRangedStayItem(newDate("2018-08-01"), newDate("2018-08-02"), properties("3")...),
+			},
+		},
+		{
+			"Ranged stays with max. 2 properties",
+			&QueryControl{MultipleItineraries: MultipleItinerariesAffectedDates, MaxProperties: 2},
+			[]Item{
+				NewCheckinRangeItem(newDate("2018-07-01"), newDate("2018-07-03"), properties("1")...),
+				NewCheckinRangeItem(newDate("2018-07-01"), newDate("2018-07-03"), properties("2")...),
+				NewRangedStayItem(newDate("2018-08-01"), newDate("2018-08-02"), properties("1", "2")...),
+				NewRangedStayItem(newDate("2018-08-01"), newDate("2018-08-02"), properties("3")...),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewHintBuilder(tt.control)
+			for _, c := range changes {
+				if c.nights > 0 {
+					b.AddItinerary(Property{c.property}, newDate(c.first), c.nights)
+				} else {
+					b.AddStayDates(Property{c.property}, newDate(c.first), newDate(c.last))
+				}
+			}
+			if got := b.Hint(); !reflect.DeepEqual(got.Item, tt.want) {
+				printError(t, got.Item, tt.want)
+			}
+		})
+	}
+}
+
+func TestHintBuilderSplitsExactItems(t *testing.T) {
+	var b HintBuilder
+	for i := 0; i < MaxExactItemProperties+1; i++ {
+		b.AddItinerary(Property{fmt.Sprintf("%03d", i)}, newDate("2018-07-03"), 1)
+	}
+
+	hint := b.Hint()
+	if len(hint.Item) != 2 {
+		t.Fatalf("len(Item) got %v, want: 2", len(hint.Item))
+	}
+	if n := len(hint.Item[0].Property); n != MaxExactItemProperties {
+		t.Errorf("len(Item[0].Property) got %v, want: %v", n, MaxExactItemProperties)
+	}
+	if id := hint.Item[1].Property[0].ID; id != strconv.Itoa(MaxExactItemProperties) {
+		t.Errorf("Item[1].Property[0] got %v, want: %v", id, MaxExactItemProperties)
+	}
+}
+
+func TestHintBuilderPrefersSharedExactItems(t *testing.T) {
+	var b HintBuilder
+	for i := 0; i < 10; i++ {
+		p := Property{strconv.Itoa(i)}
+		b.AddItinerary(p, newDate("2018-07-03"), 1)
+		b.AddItinerary(p, newDate("2018-07-04"), 1)
+	}
+	b.AddItinerary(Property{"x"}, newDate("2018-07-03"), 2)
+	b.AddItinerary(Property{"x"}, newDate("2018-07-04"), 3)
+
+	ids := []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9"}
+	want := []Item{
+		NewExactItem(newDate("2018-07-03"), 1, properties(ids...)...),
+		NewExactItem(newDate("2018-07-04"), 1, properties(ids...)...),
+		NewCheckinRangeItem(newDate("2018-07-03"), newDate("2018-07-04"), properties("x")...),
+	}
+	if got := b.Hint(); !reflect.DeepEqual(got.Item, want) {
+		printError(t, got.Item, want)
+	}
+}
+
+func TestHintBuilderShapes(t *testing.T) {
+	type itinerary struct {
+		property string
+		checkin  string
+		nights   int
+	}
+
+	tests := []struct {
+		name        string
+		control     *QueryControl
+		itineraries []itinerary
+		want        []Item
+	}{
+		{
+			"Exact items shared with single itineraries",
+			nil,
+			[]itinerary{
+				{"A", "2018-06-10", 1},
+				{"A", "2018-06-11", 1},
+				{"B", "2018-06-10", 1},
+				{"C", "2018-06-11", 1},
+			},
+			[]Item{
+				NewExactItem(newDate("2018-06-10"), 1, properties("A", "B")...),
+				NewExactItem(newDate("2018-06-11"), 1, properties("A", "C")...),
+			},
+		},
+		{
+			"Tie between exact items and check-in range",
+			nil,
+			[]itinerary{
+				{"A", "2018-06-10", 1},
+				{"A", "2018-06-11", 1},
+				{"B", "2018-06-10", 1},
+			},
+			[]Item{
+				NewExactItem(newDate("2018-06-10"), 1, properties("A", "B")...),
+				NewExactItem(newDate("2018-06-11"), 1, properties("A")...),
+			},
+		},
+		{
+			"Check-in range shared by all properties",
+			&QueryControl{MultipleItineraries: MultipleItinerariesCheckinRange},
+			[]itinerary{
+				{"A", "2018-06-10", 1},
+				{"A", "2018-06-11", 2},
+				{"B", "2018-06-10", 2},
+				{"B", "2018-06-11", 1},
+			},
+			[]Item{
+				NewCheckinRangeItem(newDate("2018-06-10"), newDate("2018-06-11"), properties("A", "B")...),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewHintBuilder(tt.control)
+			for _, it := range tt.itineraries {
+				b.AddItinerary(Property{it.property}, newDate(it.checkin), it.nights)
+			}
+			if got := b.Hint(); !reflect.DeepEqual(got.Item, tt.want) {
+				printError(t, got.Item, tt.want)
+			}
+		})
+	}
+}
+
+func TestHintBuilderLongStay(t *testing.T) {
+	var b HintBuilder
+	b.AddItinerary(Property{"1"}, newDate("2018-07-03"), 200)
+
+	want := []Item{
+		NewRangedStayItem(newDate("2018-07-03"), newDate("2019-01-18"), properties("1")...),
+	}
+	if got := b.Hint(); !reflect.DeepEqual(got.Item, want) {
+		printError(t, got.Item, want)
+	}
+}
+
+func TestHintBuilderEmpty(t *testing.T) {
+	var b HintBuilder
+	b.AddItinerary(Property{"1"}, newDate("2018-07-03"), 0)
+
+	if got := b.Hint(); got.Item == nil || len(got.Item) != 0 {
+		t.Errorf("Hint got %+v, want no items", got)
+	}
+}
@@ -8,7 +8,6 @@ import (
 	"testing"
 	"time"
 
-	cdt "github.com/f-go/go-custom-datetime"
 	"github.com/sergi/go-diff/diffmatchpatch"
 )
 
@@ -20,8 +19,8 @@ func TestHintRequestStruct(t *testing.T) {
 	}
 
 	t.Run("Test HintRequest Struct", func(t *testing.T) {
-		timestamp, _ := cdt.NewCustomDateTime("2019-06-03T22:59:48Z")
-		lastFetchTime, _ := cdt.NewCustomDateTime("2019-06-03T22:54:40Z")
+		timestamp, _ := NewDateTime("2019-06-03T22:59:48Z")
+		lastFetchTime, _ := NewDateTime("2019-06-03T22:54:40Z")
 		want := HintRequest{
 			ID:            "request",
 			Timestamp:     timestamp,
@@ -52,11 +51,16 @@ func printError(t *testing.T, got, want interface{}) {
 	t.Errorf("\ngot:  %v\nwant: %v\ndiff: %v", got, want, dmp.DiffPrettyText(diffs))
 }
 
-func newCustomDate(value string) cdt.CustomDate {
-	d, _ := cdt.NewCustomDate(value)
+func newDate(value string) Date {
+	d, _ := NewDate(value)
 	return d
 }
 
+func newTime(value string) Time {
+	t, _ := NewTime(value)
+	return t
+}
+
 func TestHintStruct(t *testing.T) {
 	tests := []struct {
 		name string
@@ -82,14 +86,14 @@ func TestHintStruct(t *testing.T) {
 							{"12345"},
 						},
 						Stay: &Stay{
-							CheckInDate:  newCustomDate("2018-07-03"),
+							CheckInDate:  newDate("2018-07-03"),
 							LengthOfStay: 3,
 						},
 					},
 					{
 						Property: []Property{{"12345"}},
 						Stay: &Stay{
-							CheckInDate:  newCustomDate("2018-07-03"),
+							CheckInDate:  newDate("2018-07-03"),
 							LengthOfStay: 4,
 						},
 					},
@@ -108,8 +112,8 @@ func TestHintStruct(t *testing.T) {
 							{"12345"},
 							{"67890"},
 						},
-						FirstDate: newCustomDate("2018-07-03"),
-						LastDate:  newCustomDate("2018-07-06"),
+						FirstDate: newDate("2018-07-03"),
+						LastDate:  newDate("2018-07-06"),
 					},
 				},
 			},
@@ -126,8 +130,8 @@ func TestHintStruct(t *testing.T) {
 							{"12345"},
 						},
 						StaysIncludingRange: &StaysIncludingRange{
-							FirstDate: newCustomDate("2018-07-03"),
-							LastDate:  newCustomDate("2018-07-06"),
+							FirstDate: newDate("2018-07-03"),
+							LastDate:  newDate("2018-07-06"),
 						},
 					},
 					{
@@ -135,7 +139,7 @@ func TestHintStruct(t *testing.T) {
 							{"67890"},
 						},
 						StaysIncludingRange: &StaysIncludingRange{
-							FirstDate: newCustomDate("2018-07-03"),
+							FirstDate: newDate("2018-07-03"),
 						},
 					},
 				},
@@ -177,16 +181,97 @@ func TestHintStruct(t *testing.T) {
 				if !reflect.DeepEqual(item.FirstDate, got.Item[idx].FirstDate) {
 					printError(
 						t,
-						time.Time(item.FirstDate).Format(cdt.CustomDateFormat),
-						time.Time(got.Item[idx].FirstDate).Format(cdt.CustomDateFormat))
+						time.Time(item.FirstDate).Format(DateFormat),
+						time.Time(got.Item[idx].FirstDate).Format(DateFormat))
 				}
 				if !reflect.DeepEqual(item.LastDate, got.Item[idx].LastDate) {
 					printError(
 						t,
-						time.Time(item.LastDate).Format(cdt.CustomDateFormat),
-						time.Time(got.Item[idx].LastDate).Format(cdt.CustomDateFormat))
+						time.Time(item.LastDate).Format(DateFormat),
+						time.Time(got.Item[idx].LastDate).Format(DateFormat))
 				}
 			}
 		})
 	}
 }
+
+func TestNewItem(t *testing.T) {
+	tests := []struct {
+		name string
+		got  Item
+		want Item
+	}{
+		{
+			"Exact itinerary",
+			NewExactItem(newDate("2018-07-03"), 3, Property{"12345"}, Property{"67890"}),
+			Item{
+				Property: []Property{{"12345"}, {"67890"}},
+				Stay: &Stay{
+					CheckInDate:  newDate("2018-07-03"),
+					LengthOfStay: 3,
+				},
+			},
+		},
+		{
+			"Check-in range",
+			NewCheckinRangeItem(newDate("2018-07-03"), newDate("2018-07-06"), Property{"12345"}),
+			Item{
+				Property:  []Property{{"12345"}},
+				FirstDate: newDate("2018-07-03"),
+				LastDate:  newDate("2018-07-06"),
+			},
+		},
+		{
+			"Ranged stay",
+			NewRangedStayItem(newDate("2018-07-03"), newDate("2018-07-06"), Property{"12345"}),
+			Item{
+				Property: []Property{{"12345"}},
+				StaysIncludingRange: &StaysIncludingRange{
+					FirstDate: newDate("2018-07-03"),
+					LastDate:  newDate("2018-07-06"),
+				},
+			},
+		},
+		{
+			"Ranged stay for a single night",
+			NewRangedStayItem(newDate("2018-07-03"), newDate("2018-07-03"), Property{"67890"}),
+			Item{
+				Property: []Property{{"67890"}},
+				StaysIncludingRange: &StaysIncludingRange{
+					FirstDate: newDate("2018-07-03"),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.got, tt.want) {
+				printError(t, tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHintMarshalOmitsUnsetDates(t *testing.T) {
+	hint := Hint{
+		Item: []Item{
+			NewExactItem(newDate("2018-07-03"), 3, Property{"12345"}),
+			NewRangedStayItem(newDate("2018-07-03"), newDate("2018-07-03"), Property{"67890"}),
+		},
+	}
+
+	got, err := xml.Marshal(hint)
+	if err != nil {
+		t.Errorf("Marshal failed with error: %v", err)
+		return
+	}
+
+	want := "<Hint>" +
+		"<Item><Property>12345</Property><Stay><CheckInDate>2018-07-03</CheckInDate><LengthOfStay>3</LengthOfStay></Stay></Item>" +
+		"<Item><Property>67890</Property><StaysIncludingRange><FirstDate>2018-07-03</FirstDate></StaysIncludingRange></Item>" +
+		"</Hint>"
+	if string(got) != want {
+		printError(t, string(got), want)
+	}
+}
@@ -0,0 +1,97 @@
+package gha
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// Hotel List Feed that defines the properties you provide prices for. The
+// <id> of a <listing> is the property ID used in all other messages.
+//
+// https://developers.google.com/hotels/hotel-prices/dev-guide/hotel-list-feed
+type Listings struct {
+	XMLName  xml.Name  `xml:"listings"`
+	Language string    `xml:"language"` // ISO 639-1 language of the listing content, e.g. "en"
+	Listing  []Listing `xml:"listing,omitempty"`
+}
+
+// Container for a single property of the Hotel List Feed.
+type Listing struct {
+	ID        string  `xml:"id"`
+	Name      string  `xml:"name"`
+	Address   Address `xml:"address"`
+	Country   string  `xml:"country"` // ISO 3166-1 alpha-2, e.g. "GB"
+	Latitude  float64 `xml:"latitude,omitempty"`
+	Longitude float64 `xml:"longitude,omitempty"`
+	Phone     []Phone `xml:"phone,omitempty"`
+	Category  string  `xml:"category,omitempty"` // e.g. "hotel"
+}
+
+// Returns the property that is priced under the ID of the listing.
+func (l *Listing) Property() Property {
+	return Property{l.ID}
+}
+
+// Address of a listing, given as structured components.
+type Address struct {
+	Format    string             `xml:"format,attr"` // [simple]
+	Component []AddressComponent `xml:"component,omitempty"`
+}
+
+// Returns the value of the component with the given name, e.g. "city", or an
+// empty string if the address has no such component.
+func (a *Address) Get(name string) string {
+	for _, c := range a.Component {
+		if c.Name == name {
+			return c.Value
+		}
+	}
+	return ""
+}
+
+// A part of an address.
+type AddressComponent struct {
+	Name  string `xml:"name,attr"` // [addr1|addr2|addr3|city|province|postal_code]
+	Value string `xml:",chardata"`
+}
+
+// Phone number of a listing.
+type Phone struct {
+	Type   string `xml:"type,attr"` // [main|tollfree|fax|mobile]
+	Number string `xml:",chardata"`
+}
+
+// Reads a Hotel List Feed.
+func DecodeHotelList(r io.Reader) (*Listings, error) {
+	var l Listings
+	if err := decodeFile(r, &l); err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// Writes the given listings as Hotel List Feed, including the XML declaration
+// and the reference to the feed schema.
+func EncodeHotelList(w io.Writer, l *Listings) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	start := xml.StartElement{
+		Name: xml.Name{Local: "listings"},
+		Attr: []xml.Attr{
+			{Name: xml.Name{Local: "xmlns:xsi"}, Value: "http://www.w3.org/2001/XMLSchema-instance"},
+			{Name: xml.Name{Local: "xsi:noNamespaceSchemaLocation"}, Value: "http://www.gstatic.com/localfeed/local_feed.xsd"},
+		},
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.EncodeElement(l, start); err != nil {
+		return err
+	}
+	if err := enc.Flush(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
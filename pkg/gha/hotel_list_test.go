@@ -0,0 +1,100 @@
+package gha
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestHotelListFeed(t *testing.T) {
+	f, err := os.Open("./testdata/HotelList-Example.xml")
+	if err != nil {
+		t.Errorf("File reading error %v", err)
+		return
+	}
+	defer f.Close()
+
+	got, err := DecodeHotelList(f)
+	if err != nil {
+		t.Errorf("Parsing feed failed with error: %v", err)
+		return
+	}
+
+	want := Listings{
+		XMLName:  got.XMLName,
+		Language: "en",
+		Listing: []Listing{
+			{
+				ID:   "060773",
+				Name: "Belgrave House",
+				Address: Address{
+					Format: "simple",
+					Component: []AddressComponent{
+						{"addr1", "6 Acacia Ave"},
+						{"addr2", "Floor 5"},
+						{"city", "London"},
+						{"province", "Greater London"},
+						{"postal_code", "SW1W 9TQ"},
+					},
+				},
+				Country:   "GB",
+				Latitude:  51.494159,
+				Longitude: -0.147218,
+				Phone: []Phone{
+					{"main", "+44 20 7730 1234"},
+					{"fax", "+44 20 7730 1235"},
+				},
+				Category: "hotel",
+			},
+			{
+				ID:   "052213",
+				Name: "Hotel Adlon",
+				Address: Address{
+					Format: "simple",
+					Component: []AddressComponent{
+						{"addr1", "Unter den Linden 77"},
+						{"city", "Berlin"},
+						{"postal_code", "10117"},
+					},
+				},
+				Country:   "DE",
+				Latitude:  52.5158,
+				Longitude: 13.3806,
+				Phone: []Phone{
+					{"main", "+49 30 22610"},
+				},
+				Category: "hotel",
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(*got, want) {
+		printError(t, *got, want)
+	}
+	if city := got.Listing[1].Address.Get("city"); city != "Berlin" {
+		t.Errorf("city got %v, want: Berlin", city)
+	}
+	if p := got.Listing[0].Property(); p.ID != "060773" {
+		t.Errorf("Property got %v, want: 060773", p.ID)
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeHotelList(&buf, got); err != nil {
+		t.Errorf("Encoding feed failed with error: %v", err)
+		return
+	}
+	if !strings.Contains(buf.String(), `xsi:noNamespaceSchemaLocation="http://www.gstatic.com/localfeed/local_feed.xsd"`) {
+		t.Errorf("Encoded feed has no schema location:\n%s", buf.String())
+	}
+
+	again, err := DecodeHotelList(&buf)
+	if err != nil {
+		t.Errorf("Parsing encoded feed failed with error: %v", err)
+		return
+	}
+	if !reflect.DeepEqual(again, got) {
+		printError(t, again, got)
+	}
+}
@@ -0,0 +1,256 @@
+package gha
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Landing pages file that defines the points of sale, i.e. the websites a user
+// can book a room on. Each point of sale defines when it is eligible and the
+// URL template of the landing page.
+//
+// https://developers.google.com/hotels/hotel-prices/dev-guide/pos-syntax
+type PointsOfSale struct {
+	XMLName     xml.Name      `xml:"PointsOfSale"`
+	PointOfSale []LandingPage `xml:",omitempty"`
+}
+
+// Definition of a point of sale in the landing pages file. The ID is
+// referenced by the <PointOfSale> elements of <AllowablePointsOfSale>.
+type LandingPage struct {
+	ID    string  `xml:"id,attr"`
+	Match []Match `xml:",omitempty"`
+	URL   string  `xml:""`
+}
+
+// Criteria that decide whether a point of sale is eligible for a user. All
+// criteria that are set must match the user context.
+//
+// * The Status field:
+//   - yes:     The point of sale is eligible if the criteria match.
+//   - never:   The point of sale is not eligible if the criteria match, even
+//              if another <Match> with status "yes" matches.
+//   - default: The point of sale is used if the criteria match and no other
+//              point of sale is eligible.
+type Match struct {
+	Status   string     `xml:"status,attr"`             // [yes|never|default]
+	Country  string     `xml:"country,attr,omitempty"`  // ISO 3166-1 alpha-2, e.g. "US"
+	Device   DeviceType `xml:"device,attr,omitempty"`   // [desktop|mobile|tablet]
+	Language string     `xml:"language,attr,omitempty"` // ISO 639-1, e.g. "en"
+	Currency string     `xml:"currency,attr,omitempty"` // ISO 4217, e.g. "USD"
+}
+
+// Returns true if all criteria of the match are met by the given context.
+func (m *Match) Matches(ctx UserContext) bool {
+	return matchValue(m.Country, ctx.Country) &&
+		matchValue(string(m.Device), string(ctx.Device)) &&
+		matchValue(m.Language, ctx.Language) &&
+		matchValue(m.Currency, ctx.Currency)
+}
+
+func matchValue(criterion, value string) bool {
+	return criterion == "" || strings.EqualFold(criterion, value)
+}
+
+// The user a landing page is resolved or a rate rule is evaluated for.
+type UserContext struct {
+	Country  string     // ISO 3166-1 alpha-2, e.g. "US"
+	Device   DeviceType // [desktop|mobile|tablet]
+	Language string     // ISO 639-1, e.g. "en"
+	Currency string     // ISO 4217, e.g. "USD"
+
+	SignedIn       bool     // user is signed in
+	Member         bool     // user is a member of the loyalty program
+	Qualifications []string // e.g. "senior" or "government"
+}
+
+// Error returned if no point of sale is eligible for a rate and user.
+var ErrNoPointOfSale = errors.New("gha: no eligible point of sale")
+
+// Returns the landing page for the given user and the points of sale the
+// rate allows, see AllowablePointsOfSale. The first eligible point of sale of
+// the file wins.
+func (p *PointsOfSale) Select(allowed *AllowablePointsOfSale, ctx UserContext) (*LandingPage, error) {
+	var fallback *LandingPage
+
+	for i := range p.PointOfSale {
+		lp := &p.PointOfSale[i]
+		if !allowed.allows(lp.ID) {
+			continue
+		}
+
+		yes, never, def := false, false, false
+		for j := range lp.Match {
+			if !lp.Match[j].Matches(ctx) {
+				continue
+			}
+			switch lp.Match[j].Status {
+			case "yes":
+				yes = true
+			case "never":
+				never = true
+			case "default":
+				def = true
+			}
+		}
+
+		switch {
+		case never:
+		case yes:
+			return lp, nil
+		case def && fallback == nil:
+			fallback = lp
+		}
+	}
+
+	if fallback == nil {
+		return nil, ErrNoPointOfSale
+	}
+	return fallback, nil
+}
+
+// Returns true if the point of sale with the given ID is allowed. All points
+// of sale are allowed if the element is not set.
+func (a *AllowablePointsOfSale) allows(id string) bool {
+	if a == nil || len(a.PointOfSale) == 0 {
+		return true
+	}
+	for _, pos := range a.PointOfSale {
+		if pos.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the booking URL for the given result, rate and user. The rate is
+// the <Rate> of the result or of one of its room bundles, or one of their
+// <Rates>; values it does not set are inherited, see Rate.Inherit.
+func (p *PointsOfSale) Resolve(r *Result, rate *Rate, ctx UserContext) (string, error) {
+	_, e := r.resolveRate(rate)
+	lp, err := p.Select(e.AllowablePointsOfSale, ctx)
+	if err != nil {
+		return "", err
+	}
+	return lp.Expand(r, rate, ctx), nil
+}
+
+// Returns the room of the given rate and the rate with the values it inherits:
+// from the result for the <Rate> of the result and its <Rates>, and from the
+// room bundle for the rates in the <Rates> of a room bundle, as in
+// Result.EffectiveRates and RoomBundle.EffectiveRates. A rate that belongs to
+// neither is treated as rate of the result.
+func (r *Result) resolveRate(rate *Rate) (string, Rate) {
+	for i := range r.RoomBundle {
+		b := &r.RoomBundle[i]
+		if rate == &b.Rate {
+			return b.RoomID, rate.Inherit(Rate{})
+		}
+		if b.Rates == nil {
+			continue
+		}
+		for j := range b.Rates.Rate {
+			if rate == &b.Rates.Rate[j] {
+				return b.RoomID, rate.Inherit(b.Rate)
+			}
+		}
+	}
+	return r.RoomID, rate.Inherit(r.Rate)
+}
+
+// Matches a variable of a URL template, e.g. (CHECKINDAY).
+var urlVariable = regexp.MustCompile(`\(([A-Z0-9-]+)\)`)
+
+// Returns the URL of the landing page with all variables replaced by the
+// values of the given result, rate and user, see Resolve for the rates.
+// Values are escaped for the path before the "?" and for the query after it.
+// Unknown variables are kept. NUM-ADULTS defaults to DefaultOccupancy, as in
+// Rate.Price.
+//
+// Supported variables:
+//   (PARTNER-HOTEL-ID), (PARTNER-ROOM-ID), (RATE-RULE-ID), (POINT-OF-SALE-ID),
+//   (CHECKINDAY), (CHECKINMONTH), (CHECKINYEAR),
+//   (CHECKOUTDAY), (CHECKOUTMONTH), (CHECKOUTYEAR), (LENGTH),
+//   (NUM-ADULTS), (NUM-CHILDREN), (PARTNER-CURRENCY),
+//   (USER-COUNTRY), (USER-DEVICE), (USER-LANGUAGE), (USER-CURRENCY),
+//   (CUSTOM1) to (CUSTOM5)
+func (lp *LandingPage) Expand(r *Result, rate *Rate, ctx UserContext) string {
+	room, e := r.resolveRate(rate)
+	checkin := time.Time(r.Checkin)
+	checkout := checkin.AddDate(0, 0, int(r.Nights))
+
+	adults, children := e.guests(), 0
+	if e.OccupancyDetails != nil {
+		adults = int(e.OccupancyDetails.NumAdults)
+		if e.OccupancyDetails.Children != nil {
+			children = len(e.OccupancyDetails.Children.Child)
+		}
+	}
+
+	currency := ""
+	if e.Baserate != nil {
+		currency = e.Baserate.Currency
+	}
+
+	values := map[string]string{
+		"PARTNER-HOTEL-ID": r.Property.ID,
+		"PARTNER-ROOM-ID":  room,
+		"RATE-RULE-ID":     e.RateRuleID,
+		"POINT-OF-SALE-ID": lp.ID,
+		"CHECKINDAY":       checkin.Format("02"),
+		"CHECKINMONTH":     checkin.Format("01"),
+		"CHECKINYEAR":      checkin.Format("2006"),
+		"CHECKOUTDAY":      checkout.Format("02"),
+		"CHECKOUTMONTH":    checkout.Format("01"),
+		"CHECKOUTYEAR":     checkout.Format("2006"),
+		"LENGTH":           strconv.Itoa(int(r.Nights)),
+		"NUM-ADULTS":       strconv.Itoa(adults),
+		"NUM-CHILDREN":     strconv.Itoa(children),
+		"PARTNER-CURRENCY": currency,
+		"USER-COUNTRY":     ctx.Country,
+		"USER-DEVICE":      string(ctx.Device),
+		"USER-LANGUAGE":    ctx.Language,
+		"USER-CURRENCY":    ctx.Currency,
+		"CUSTOM1":          e.Custom1,
+		"CUSTOM2":          e.Custom2,
+		"CUSTOM3":          e.Custom3,
+		"CUSTOM4":          e.Custom4,
+		"CUSTOM5":          e.Custom5,
+	}
+
+	expand := func(template string, escape func(string) string) string {
+		return urlVariable.ReplaceAllStringFunc(template, func(v string) string {
+			value, ok := values[v[1:len(v)-1]]
+			if !ok {
+				return v
+			}
+			return escape(value)
+		})
+	}
+	path, query := strings.TrimSpace(lp.URL), ""
+	if i := strings.Index(path, "?"); i >= 0 {
+		path, query = path[:i], path[i:]
+	}
+	return expand(path, url.PathEscape) + expand(query, url.QueryEscape)
+}
+
+// Reads a landing pages file.
+func DecodePointsOfSale(r io.Reader) (*PointsOfSale, error) {
+	var p PointsOfSale
+	if err := decodeFile(r, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Writes the given points of sale as landing pages file, including the XML
+// declaration.
+func EncodePointsOfSale(w io.Writer, p *PointsOfSale) error {
+	return encodeFile(w, p)
+}
@@ -0,0 +1,186 @@
+package gha
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func readPointsOfSale(t *testing.T) *PointsOfSale {
+	var p *PointsOfSale
+	readTestFile(t, "PointsOfSale-Example.xml", func(r io.Reader) (err error) {
+		p, err = DecodePointsOfSale(r)
+		return err
+	})
+	return p
+}
+
+func TestPointsOfSaleFile(t *testing.T) {
+	got := readPointsOfSale(t)
+
+	if len(got.PointOfSale) != 3 {
+		t.Fatalf("len(PointOfSale) got %v, want: 3", len(got.PointOfSale))
+	}
+	wantMatch := []Match{
+		{Status: "yes", Country: "US"},
+		{Status: "never", Device: "tablet"},
+	}
+	if !reflect.DeepEqual(got.PointOfSale[0].Match, wantMatch) {
+		printError(t, got.PointOfSale[0].Match, wantMatch)
+	}
+
+	var buf bytes.Buffer
+	if err := EncodePointsOfSale(&buf, got); err != nil {
+		t.Fatalf("Encoding file failed with error: %v", err)
+	}
+	again, err := DecodePointsOfSale(&buf)
+	if err != nil {
+		t.Fatalf("Parsing encoded file failed with error: %v", err)
+	}
+	if !reflect.DeepEqual(again, got) {
+		printError(t, again, got)
+	}
+}
+
+func TestPointsOfSaleResolve(t *testing.T) {
+	pos := readPointsOfSale(t)
+
+	result := Result{
+		Property: Property{"8251"},
+		Checkin:  newDate("2018-06-30"),
+		Nights:   2,
+		Rate: Rate{
+			Baserate: money("62.18", "USD"),
+			Custom1:  "base",
+		},
+		Rates: &Rates{
+			Rate: []Rate{
+				{
+					RateRuleID: "rule-951",
+					Occupancy:  2,
+					OccupancyDetails: &OccupancyDetails{
+						NumAdults: 1,
+						Children:  &Children{Child: []Child{{17}}},
+					},
+					Custom1: "abc 4",
+				},
+				{
+					RateRuleID: "site1-only",
+					AllowablePointsOfSale: &AllowablePointsOfSale{
+						PointOfSale: []PointOfSale{{"mobile.yourhotelpartnersite.com"}},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name string
+		rate *Rate
+		ctx  UserContext
+		want string
+		err  error
+	}{
+		{
+			"Base rate for US user",
+			&result.Rate,
+			UserContext{Country: "US", Device: "desktop"},
+			"https://www.yourhotelpartnersite.com/book?hotel=8251&checkin=2018-06-30&checkout=2018-07-02&adults=2&children=0&rate=base&device=desktop",
+			nil,
+		},
+		{
+			"Conditional rate with occupancy details",
+			&result.Rates.Rate[0],
+			UserContext{Country: "us", Device: "mobile"},
+			"https://www.yourhotelpartnersite.com/book?hotel=8251&checkin=2018-06-30&checkout=2018-07-02&adults=1&children=1&rate=abc+4&device=mobile",
+			nil,
+		},
+		{
+			"Never match excludes point of sale",
+			&result.Rate,
+			UserContext{Country: "US", Device: "tablet"},
+			"https://www.example.com/hotel/8251",
+			nil,
+		},
+		{
+			"Rate restricted to mobile site",
+			&result.Rates.Rate[1],
+			UserContext{Country: "US", Device: "mobile", Language: "de"},
+			"https://m.yourhotelpartnersite.com/de/hotel/8251?nights=2&currency=USD&promo=(PROMO)",
+			nil,
+		},
+		{
+			"Rate restricted to mobile site for desktop user",
+			&result.Rates.Rate[1],
+			UserContext{Country: "US", Device: "desktop", Language: "de"},
+			"",
+			ErrNoPointOfSale,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pos.Resolve(&result, tt.rate, tt.ctx)
+			if err != tt.err {
+				t.Fatalf("Resolve got error %v, want: %v", err, tt.err)
+			}
+			if got != tt.want {
+				printError(t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLandingPageExpand(t *testing.T) {
+	lp := LandingPage{
+		ID:  "site",
+		URL: "https://www.example.com/(PARTNER-HOTEL-ID)/(PARTNER-ROOM-ID)?room=(PARTNER-ROOM-ID)&adults=(NUM-ADULTS)",
+	}
+	result := Result{
+		Property: Property{"Hotel One"},
+		RoomID:   "Standard Room",
+		Checkin:  newDate("2018-06-30"),
+		Nights:   2,
+		Rate:     Rate{Baserate: money("62.18", "USD")},
+		RoomBundle: []RoomBundle{
+			{
+				RoomID: "Family Suite",
+				Rate:   Rate{Baserate: money("120.00", "USD"), Occupancy: 4},
+				Rates: &Rates{
+					Rate: []Rate{{RateRuleID: "mobile", Baserate: money("110.00", "USD")}},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name string
+		rate *Rate
+		want string
+	}{
+		{
+			"Result rate",
+			&result.Rate,
+			"https://www.example.com/Hotel%20One/Standard%20Room?room=Standard+Room&adults=2",
+		},
+		{
+			"Room bundle",
+			&result.RoomBundle[0].Rate,
+			"https://www.example.com/Hotel%20One/Family%20Suite?room=Family+Suite&adults=4",
+		},
+		{
+			"Rate of room bundle",
+			&result.RoomBundle[0].Rates.Rate[0],
+			"https://www.example.com/Hotel%20One/Family%20Suite?room=Family+Suite&adults=4",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lp.Expand(&result, tt.rate, UserContext{}); got != tt.want {
+				printError(t, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,372 @@
+package gha
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Exact decimal number such as 278.33, represented by an integer coefficient
+// and the number of its fractional digits. The number of fractional digits is
+// kept as given, so "2.00" is marshalled as "2.00" and not as "2".
+//
+// Values are limited to 18 significant digits. The zero value is 0.
+type Decimal struct {
+	coef  int64
+	scale uint8
+}
+
+// Max. number of digits of a Decimal.
+const maxDecimalDigits = 18
+
+// Error returned if the result of an operation has more than 18 digits
+// before the decimal separator, or more fractional digits are requested.
+var ErrDecimalOverflow = errors.New("gha: decimal overflow")
+
+// Returns the decimal coef * 10^-scale, e.g. NewDecimal(27833, 2) is 278.33.
+// coef must not have more than 18 digits and scale must not exceed 18.
+func NewDecimal(coef int64, scale uint8) Decimal {
+	return Decimal{coef, scale}
+}
+
+// Parses a decimal number in plain notation, e.g. "-12.50".
+func ParseDecimal(s string) (Decimal, error) {
+	v := strings.TrimSpace(s)
+	neg := strings.HasPrefix(v, "-")
+	v = strings.TrimPrefix(strings.TrimPrefix(v, "-"), "+")
+
+	digits := v
+	var scale int
+	if i := strings.IndexByte(v, '.'); i >= 0 {
+		digits = v[:i] + v[i+1:]
+		scale = len(v) - i - 1
+	}
+	if digits == "" || strings.TrimLeft(digits, "0123456789") != "" {
+		return Decimal{}, fmt.Errorf("gha: invalid decimal %q", s)
+	}
+	if len(strings.TrimLeft(digits, "0")) > maxDecimalDigits || scale > maxDecimalDigits {
+		return Decimal{}, fmt.Errorf("gha: decimal %q has more than %d digits", s, maxDecimalDigits)
+	}
+
+	coef, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return Decimal{}, fmt.Errorf("gha: invalid decimal %q", s)
+	}
+	if neg {
+		coef = -coef
+	}
+	return Decimal{coef, uint8(scale)}, nil
+}
+
+// Like ParseDecimal but panics if s is not a valid decimal. It simplifies
+// the initialization of constants and test data.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// Returns the number of fractional digits.
+func (d Decimal) Scale() uint8 {
+	return d.scale
+}
+
+// Returns -1, 0 or +1 for negative, zero and positive numbers.
+func (d Decimal) Sign() int {
+	switch {
+	case d.coef < 0:
+		return -1
+	case d.coef > 0:
+		return 1
+	}
+	return 0
+}
+
+// Returns true if the number is zero, regardless of its scale.
+func (d Decimal) IsZero() bool {
+	return d.coef == 0
+}
+
+// Returns the coefficient of d at the given scale, which must not be lower
+// than the scale of d.
+func (d Decimal) scaled(scale uint8) *big.Int {
+	c := big.NewInt(d.coef)
+	if scale > d.scale {
+		c.Mul(c, bigPow10(int(scale-d.scale)))
+	}
+	return c
+}
+
+func maxScale(a, b Decimal) uint8 {
+	if a.scale > b.scale {
+		return a.scale
+	}
+	return b.scale
+}
+
+// Returns d + o with the larger scale of both, see Mul for results with more
+// than 18 digits.
+func (d Decimal) Add(o Decimal) (Decimal, error) {
+	s := maxScale(d, o)
+	return fitDecimal(new(big.Int).Add(d.scaled(s), o.scaled(s)), int(s))
+}
+
+// Returns d - o with the larger scale of both, see Add.
+func (d Decimal) Sub(o Decimal) (Decimal, error) {
+	return d.Add(o.Neg())
+}
+
+// Returns -d.
+func (d Decimal) Neg() Decimal {
+	return Decimal{-d.coef, d.scale}
+}
+
+// Returns d * o, its scale is the sum of both scales. A result with more
+// than 18 digits or fractional digits is rounded half up to fewer fractional
+// digits; ErrDecimalOverflow is returned if the integer part alone has more
+// than 18 digits.
+func (d Decimal) Mul(o Decimal) (Decimal, error) {
+	return fitDecimal(new(big.Int).Mul(big.NewInt(d.coef), big.NewInt(o.coef)), int(d.scale)+int(o.scale))
+}
+
+// Compares d and o numerically and returns -1 if d < o, 0 if d == o and
+// +1 if d > o.
+func (d Decimal) Cmp(o Decimal) int {
+	s := maxScale(d, o)
+	return d.scaled(s).Cmp(o.scaled(s))
+}
+
+// Rounding rules for decimal numbers.
+type RoundingMode int
+
+const (
+	// Rounds to the nearest neighbour, ties away from zero.
+	RoundHalfUp RoundingMode = iota
+
+	// Rounds to the nearest neighbour, ties to the even neighbour.
+	RoundHalfEven
+
+	// Rounds towards zero, i.e. truncates.
+	RoundDown
+
+	// Rounds away from zero.
+	RoundUp
+)
+
+// Returns d rounded to the given number of fractional digits. The result has
+// exactly the given scale, so 2.5 rounded to 2 digits is 2.50.
+func (d Decimal) Round(scale uint8, mode RoundingMode) (Decimal, error) {
+	return d.Quo(1, scale, mode)
+}
+
+// Returns d / n rounded to the given number of fractional digits, e.g. the
+// price per night of a total price. n must not be zero.
+func (d Decimal) Quo(n int64, scale uint8, mode RoundingMode) (Decimal, error) {
+	return quo(big.NewInt(d.coef), int(d.scale), n, scale, mode)
+}
+
+// Returns d * o * num / den rounded to the given number of fractional
+// digits, without rounding in between, e.g. a percentage of the nights of a
+// stay. den must not be zero.
+func mulQuo(d, o Decimal, num, den int64, scale uint8, mode RoundingMode) (Decimal, error) {
+	p := new(big.Int).Mul(big.NewInt(d.coef), big.NewInt(o.coef))
+	p.Mul(p, big.NewInt(num))
+	return quo(p, int(d.scale)+int(o.scale), den, scale, mode)
+}
+
+// Returns coef * 10^-coefScale / n rounded to the given scale.
+func quo(coef *big.Int, coefScale int, n int64, scale uint8, mode RoundingMode) (Decimal, error) {
+	if n == 0 {
+		return Decimal{}, errors.New("gha: decimal division by zero")
+	}
+	if scale > maxDecimalDigits {
+		return Decimal{}, fmt.Errorf("%w: scale %d, max. %d", ErrDecimalOverflow, scale, maxDecimalDigits)
+	}
+	num := new(big.Int).Mul(coef, bigPow10(int(scale)))
+	den := new(big.Int).Mul(big.NewInt(n), bigPow10(coefScale))
+	q := quoRound(num, den, mode)
+	if numDigits(q) > maxDecimalDigits {
+		return Decimal{}, fmt.Errorf("%w: result has more than %d digits", ErrDecimalOverflow, maxDecimalDigits)
+	}
+	return Decimal{q.Int64(), scale}, nil
+}
+
+// Returns the decimal coef * 10^-scale. Digits beyond 18 digits or 18
+// fractional digits are rounded half up; returns ErrDecimalOverflow if the
+// integer part has more than 18 digits.
+func fitDecimal(coef *big.Int, scale int) (Decimal, error) {
+	drop := scale - maxDecimalDigits
+	if n := numDigits(coef) - maxDecimalDigits; n > drop {
+		drop = n
+	}
+	if drop > scale {
+		drop = scale
+	}
+	if drop > 0 {
+		coef = quoRound(coef, bigPow10(drop), RoundHalfUp)
+		scale -= drop
+		// Rounding up 99...9 adds a digit, followed by a zero.
+		if numDigits(coef) > maxDecimalDigits && scale > 0 {
+			coef.Quo(coef, bigPow10(1))
+			scale--
+		}
+	}
+	if numDigits(coef) > maxDecimalDigits {
+		return Decimal{}, fmt.Errorf("%w: result has more than %d digits", ErrDecimalOverflow, maxDecimalDigits)
+	}
+	return Decimal{coef.Int64(), uint8(scale)}, nil
+}
+
+// Returns num / den rounded to an integer. den must be positive.
+func quoRound(num, den *big.Int, mode RoundingMode) *big.Int {
+	if den.Sign() < 0 {
+		num, den = new(big.Int).Neg(num), new(big.Int).Neg(den)
+	}
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	twice := r.Abs(r).Lsh(r, 1)
+
+	up := false
+	switch mode {
+	case RoundHalfUp:
+		up = twice.Cmp(den) >= 0
+	case RoundHalfEven:
+		c := twice.Cmp(den)
+		up = c > 0 || (c == 0 && new(big.Int).Abs(q).Bit(0) == 1)
+	case RoundUp:
+		up = twice.Sign() != 0
+	}
+	if up {
+		if num.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q
+}
+
+// Returns the number of decimal digits of the absolute value of x.
+func numDigits(x *big.Int) int {
+	return len(new(big.Int).Abs(x).String())
+}
+
+func bigPow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// Returns the number as float, e.g. for reporting. Do not use the float for
+// further calculations.
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// Returns the number in plain notation with all fractional digits.
+func (d Decimal) String() string {
+	s := strconv.FormatInt(d.coef, 10)
+	if d.scale == 0 {
+		return s
+	}
+
+	sign := ""
+	if d.coef < 0 {
+		sign, s = "-", s[1:]
+	}
+	if n := int(d.scale) + 1 - len(s); n > 0 {
+		s = strings.Repeat("0", n) + s
+	}
+	i := len(s) - int(d.scale)
+	return sign + s[:i] + "." + s[i:]
+}
+
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Decimal) UnmarshalText(text []byte) error {
+	v, err := ParseDecimal(string(text))
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// Error returned when amounts in different currencies are combined.
+var ErrCurrencyMismatch = errors.New("gha: currency mismatch")
+
+// Number of digits after the decimal separator of the ISO 4217 currencies
+// that do not have two minor units.
+var minorUnits = map[string]uint8{
+	"BHD": 3, "BIF": 0, "CLF": 4, "CLP": 0, "DJF": 0, "GNF": 0, "IQD": 3,
+	"ISK": 0, "JOD": 3, "JPY": 0, "KMF": 0, "KRW": 0, "KWD": 3, "LYD": 3,
+	"OMR": 3, "PYG": 0, "RWF": 0, "TND": 3, "UGX": 0, "UYI": 0, "UYW": 4,
+	"VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+}
+
+// Returns the number of minor units of the given ISO 4217 currency, e.g. 0 for
+// JPY, 2 for USD and 3 for KWD.
+func MinorUnits(currency string) uint8 {
+	if n, ok := minorUnits[currency]; ok {
+		return n
+	}
+	return 2
+}
+
+// Returns the Money for the given amount in plain notation and currency.
+func NewMoney(value, currency string) (Money, error) {
+	v, err := ParseDecimal(value)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{v, currency}, nil
+}
+
+// Returns m + o. Both amounts must be in the same currency.
+func (m Money) Add(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, fmt.Errorf("%w: %s + %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+	}
+	v, err := m.Value.Add(o.Value)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{v, m.Currency}, nil
+}
+
+// Returns m - o. Both amounts must be in the same currency.
+func (m Money) Sub(o Money) (Money, error) {
+	return m.Add(Money{o.Value.Neg(), o.Currency})
+}
+
+// Compares the amounts of m and o, see Decimal.Cmp. Both amounts must be in
+// the same currency.
+func (m Money) Cmp(o Money) (int, error) {
+	if m.Currency != o.Currency {
+		return 0, fmt.Errorf("%w: %s <> %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+	}
+	return m.Value.Cmp(o.Value), nil
+}
+
+// Returns true if m and o have the same currency and the same amount,
+// regardless of the number of fractional digits.
+func (m Money) Equal(o Money) bool {
+	return m.Currency == o.Currency && m.Value.Cmp(o.Value) == 0
+}
+
+// Returns the amount rounded to the minor units of its currency.
+func (m Money) Round(mode RoundingMode) (Money, error) {
+	v, err := m.Value.Round(MinorUnits(m.Currency), mode)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{v, m.Currency}, nil
+}
+
+func (m Money) String() string {
+	return m.Value.String() + " " + m.Currency
+}
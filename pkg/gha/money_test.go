@@ -0,0 +1,248 @@
+package gha
+
+import (
+	"encoding/xml"
+	"errors"
+	"testing"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		value string
+		want  Decimal
+		str   string
+	}{
+		{"278.33", NewDecimal(27833, 2), "278.33"},
+		{"2.00", NewDecimal(200, 2), "2.00"},
+		{"3196.1", NewDecimal(31961, 1), "3196.1"},
+		{"0.05", NewDecimal(5, 2), "0.05"},
+		{"-0.5", NewDecimal(-5, 1), "-0.5"},
+		{" 1500 ", NewDecimal(1500, 0), "1500"},
+		{"+.75", NewDecimal(75, 2), "0.75"},
+		{"12.", NewDecimal(12, 0), "12"},
+		{"123456789012345678", NewDecimal(123456789012345678, 0), "123456789012345678"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseDecimal(tt.value)
+			if err != nil {
+				t.Fatalf("ParseDecimal failed with error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseDecimal got %#v, want: %#v", got, tt.want)
+			}
+			if got.String() != tt.str {
+				t.Errorf("String got %v, want: %v", got.String(), tt.str)
+			}
+		})
+	}
+
+	for _, value := range []string{"", ".", "-", "1,5", "1e3", "12.3.4", "abc", "1234567890123456789"} {
+		if _, err := ParseDecimal(value); err == nil {
+			t.Errorf("ParseDecimal(%q) did not fail", value)
+		}
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	d := MustParseDecimal
+	check := func(name string, got Decimal, err error, want string) {
+		t.Helper()
+		if err != nil {
+			t.Errorf("%s failed with error: %v", name, err)
+			return
+		}
+		if got.String() != want {
+			t.Errorf("%s got %v, want: %v", name, got, want)
+		}
+	}
+
+	sum, err := d("278.33").Add(d("25.12"))
+	if err == nil {
+		sum, err = sum.Add(d("2.00"))
+	}
+	check("Add", sum, err, "305.45")
+	if got, err := d("0.1").Add(d("0.2")); err != nil || got.Cmp(d("0.3")) != 0 {
+		t.Errorf("0.1 + 0.2 got %v, %v, want: 0.3", got, err)
+	}
+	got, err := d("10").Sub(d("0.25"))
+	check("Sub", got, err, "9.75")
+	got, err = d("19.99").Mul(d("3"))
+	check("Mul", got, err, "59.97")
+	got, err = d("100.00").Mul(d("0.15"))
+	check("Mul", got, err, "15.0000")
+	if d("2.00").Cmp(d("2")) != 0 || d("2.01").Cmp(d("2")) != 1 || d("-1").Cmp(d("0")) != -1 {
+		t.Errorf("Cmp does not compare numerically")
+	}
+}
+
+func TestDecimalOverflow(t *testing.T) {
+	d := MustParseDecimal
+
+	// Results with more than 18 digits are rounded to fewer fractional digits.
+	got, err := d("123456789012.34").Add(d("0.0000000001"))
+	if err != nil || got.String() != "123456789012.340000" {
+		t.Errorf("Add got %v, %v, want: 123456789012.340000", got, err)
+	}
+	if c := d("123456789012.34").Cmp(d("0.0000000001")); c != 1 {
+		t.Errorf("Cmp got %v, want: 1", c)
+	}
+	got, err = d("0.1234567890").Mul(d("0.1234567890"))
+	if err != nil || got.String() != "0.015241578750190521" {
+		t.Errorf("Mul got %v, %v, want: 0.015241578750190521", got, err)
+	}
+	if got, err := got.Round(0, RoundHalfUp); err != nil || got.String() != "0" {
+		t.Errorf("Round got %v, %v, want: 0", got, err)
+	}
+	got, err = d("99999999999999999.9").Add(d("0.05"))
+	if err != nil || got.String() != "100000000000000000" {
+		t.Errorf("Add got %v, %v, want: 100000000000000000", got, err)
+	}
+
+	// The integer part of a result cannot be rounded.
+	if _, err := d("999999999999999999").Add(d("1")); !errors.Is(err, ErrDecimalOverflow) {
+		t.Errorf("Add got error %v, want: %v", err, ErrDecimalOverflow)
+	}
+	if _, err := d("1000000000").Mul(d("1000000000")); !errors.Is(err, ErrDecimalOverflow) {
+		t.Errorf("Mul got error %v, want: %v", err, ErrDecimalOverflow)
+	}
+	if _, err := d("100000000000000000").Round(2, RoundHalfUp); !errors.Is(err, ErrDecimalOverflow) {
+		t.Errorf("Round got error %v, want: %v", err, ErrDecimalOverflow)
+	}
+	if _, err := d("1").Round(19, RoundHalfUp); !errors.Is(err, ErrDecimalOverflow) {
+		t.Errorf("Round to 19 digits got error %v, want: %v", err, ErrDecimalOverflow)
+	}
+	if _, err := d("1").Quo(0, 2, RoundHalfUp); err == nil {
+		t.Errorf("Quo by zero got no error")
+	}
+}
+
+func TestDecimalRound(t *testing.T) {
+	tests := []struct {
+		value string
+		scale uint8
+		mode  RoundingMode
+		want  string
+	}{
+		{"2.345", 2, RoundHalfUp, "2.35"},
+		{"-2.345", 2, RoundHalfUp, "-2.35"},
+		{"2.345", 2, RoundHalfEven, "2.34"},
+		{"2.355", 2, RoundHalfEven, "2.36"},
+		{"2.3451", 2, RoundHalfEven, "2.35"},
+		{"2.349", 2, RoundDown, "2.34"},
+		{"-2.349", 2, RoundDown, "-2.34"},
+		{"2.341", 2, RoundUp, "2.35"},
+		{"2.340", 2, RoundUp, "2.34"},
+		{"1234.5", 0, RoundHalfUp, "1235"},
+		{"2.5", 2, RoundHalfUp, "2.50"},
+	}
+
+	for _, tt := range tests {
+		got, err := MustParseDecimal(tt.value).Round(tt.scale, tt.mode)
+		if err != nil || got.String() != tt.want {
+			t.Errorf("Round(%v, %v, %v) got %v, %v, want: %v", tt.value, tt.scale, tt.mode, got, err, tt.want)
+		}
+	}
+}
+
+func TestDecimalQuo(t *testing.T) {
+	tests := []struct {
+		value string
+		n     int64
+		scale uint8
+		mode  RoundingMode
+		want  string
+	}{
+		{"100.00", 3, 2, RoundHalfUp, "33.33"},
+		{"100.00", 3, 2, RoundUp, "33.34"},
+		{"200", 3, 2, RoundHalfUp, "66.67"},
+		{"-200", 3, 2, RoundHalfUp, "-66.67"},
+		{"200", -3, 2, RoundDown, "-66.66"},
+		{"0.05", 2, 2, RoundHalfEven, "0.02"},
+		{"0.07", 2, 2, RoundHalfEven, "0.04"},
+		{"10.005", 1, 2, RoundHalfUp, "10.01"},
+		{"15001", 2, 0, RoundHalfEven, "7500"},
+	}
+
+	for _, tt := range tests {
+		got, err := MustParseDecimal(tt.value).Quo(tt.n, tt.scale, tt.mode)
+		if err != nil || got.String() != tt.want {
+			t.Errorf("Quo(%v, %v, %v, %v) got %v, %v, want: %v", tt.value, tt.n, tt.scale, tt.mode, got, err, tt.want)
+		}
+	}
+}
+
+func TestMoneyMarshalKeepsDigits(t *testing.T) {
+	for _, example := range []string{
+		`<Money currency="USD">278.33</Money>`,
+		`<Money currency="USD">2.00</Money>`,
+		`<Money currency="JPY">15000</Money>`,
+		`<Money currency="KWD">12.125</Money>`,
+	} {
+		var m Money
+		if err := xml.Unmarshal([]byte(example), &m); err != nil {
+			t.Errorf("Unmarshal data failed. %v", err)
+			continue
+		}
+		got, err := xml.Marshal(m)
+		if err != nil {
+			t.Errorf("Marshal data failed. %v", err)
+			continue
+		}
+		if string(got) != example {
+			t.Errorf("Marshal got %s, want: %s", got, example)
+		}
+	}
+
+	var m Money
+	if err := xml.Unmarshal([]byte(`<Money currency="USD">12,50</Money>`), &m); err == nil {
+		t.Errorf("Unmarshal of an invalid amount did not fail")
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	baserate, tax, fees := money("278.33", "USD"), money("25.12", "USD"), money("2.00", "USD")
+
+	total, err := baserate.Add(*tax)
+	if err == nil {
+		total, err = total.Add(*fees)
+	}
+	if err != nil {
+		t.Fatalf("Add failed with error: %v", err)
+	}
+	if !total.Equal(*money("305.45", "USD")) {
+		t.Errorf("Total got %v, want: 305.45 USD", total)
+	}
+
+	if c, err := total.Cmp(*baserate); err != nil || c != 1 {
+		t.Errorf("Cmp got %v, %v, want: 1, nil", c, err)
+	}
+	if _, err := baserate.Add(*money("1", "EUR")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Add got %v, want: %v", err, ErrCurrencyMismatch)
+	}
+	if _, err := baserate.Cmp(*money("1", "EUR")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Cmp got %v, want: %v", err, ErrCurrencyMismatch)
+	}
+	if got, _ := baserate.Sub(*tax); got.String() != "253.21 USD" {
+		t.Errorf("Sub got %v, want: 253.21 USD", got)
+	}
+}
+
+func TestMoneyRound(t *testing.T) {
+	tests := []struct {
+		value, currency, want string
+	}{
+		{"1500.5", "JPY", "1501"},
+		{"19.999", "USD", "20.00"},
+		{"3196.1", "USD", "3196.10"},
+		{"3.14159", "KWD", "3.142"},
+	}
+
+	for _, tt := range tests {
+		got, err := money(tt.value, tt.currency).Round(RoundHalfUp)
+		if err != nil || got.Value.String() != tt.want {
+			t.Errorf("Round(%v %v) got %v, %v, want: %v", tt.value, tt.currency, got, err, tt.want)
+		}
+	}
+}
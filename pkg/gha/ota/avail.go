@@ -0,0 +1,57 @@
+package ota
+
+import (
+	"encoding/xml"
+
+	"github.com/f-go/link/pkg/gha"
+)
+
+// Request that opens or closes room types and rate plans of a property and
+// sets their length of stay restrictions.
+type HotelAvailNotifRQ struct {
+	XMLName xml.Name `xml:"http://www.opentravel.org/OTA/2003/05 OTA_HotelAvailNotifRQ"`
+	Header
+	AvailStatusMessages AvailStatusMessages
+}
+
+// Container for the availability of a single property.
+type AvailStatusMessages struct {
+	HotelCode          string `xml:"HotelCode,attr"`
+	AvailStatusMessage []AvailStatusMessage
+}
+
+// Returns the property the availability belongs to.
+func (m *AvailStatusMessages) Property() gha.Property {
+	return gha.Property{ID: m.HotelCode}
+}
+
+// Availability of a room type and rate plan for a range of dates. The fields
+// are in the order of the AvailStatusMessageType sequence of the schema.
+type AvailStatusMessage struct {
+	StatusApplicationControl StatusApplicationControl
+	LengthsOfStay            *LengthsOfStay     `xml:",omitempty"`
+	RestrictionStatus        *RestrictionStatus `xml:",omitempty"`
+}
+
+// Container for length of stay restrictions.
+type LengthsOfStay struct {
+	LengthOfStay []LengthOfStay
+}
+
+// Opens or closes the dates for stays, arrivals or departures.
+type RestrictionStatus struct {
+	Status      string `xml:"Status,attr"`                // [Open|Close]
+	Restriction string `xml:"Restriction,attr,omitempty"` // [Master|Arrival|Departure]
+}
+
+// Min. or max. number of nights of a stay.
+type LengthOfStay struct {
+	Time              int    `xml:"Time,attr"`
+	MinMaxMessageType string `xml:"MinMaxMessageType,attr"` // [SetMinLOS|SetMaxLOS|SetForwardMinStay|SetForwardMaxStay]
+}
+
+// Response to a HotelAvailNotifRQ.
+type HotelAvailNotifRS struct {
+	XMLName xml.Name `xml:"http://www.opentravel.org/OTA/2003/05 OTA_HotelAvailNotifRS"`
+	Response
+}
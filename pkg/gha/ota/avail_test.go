@@ -0,0 +1,46 @@
+package ota
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHotelAvailNotifRQ(t *testing.T) {
+	var got HotelAvailNotifRQ
+	readMessage(t, "OTA_HotelAvailNotifRQ.xml", &got)
+
+	want := HotelAvailNotifRQ{
+		XMLName: got.XMLName,
+		Header:  newHeader("2019-01-23T16:20:00-04:00"),
+		AvailStatusMessages: AvailStatusMessages{
+			HotelCode: "Hotel_1",
+			AvailStatusMessage: []AvailStatusMessage{
+				{
+					StatusApplicationControl: StatusApplicationControl{
+						Start:        newDate("2019-07-01"),
+						End:          newDate("2019-07-31"),
+						InvTypeCode:  "RoomType_1",
+						RatePlanCode: "RatePlan_1",
+					},
+					RestrictionStatus: &RestrictionStatus{Status: "Open", Restriction: "Master"},
+					LengthsOfStay: &LengthsOfStay{LengthOfStay: []LengthOfStay{
+						{Time: 2, MinMaxMessageType: "SetMinLOS"},
+					}},
+				},
+				{
+					StatusApplicationControl: StatusApplicationControl{
+						Start:        newDate("2019-07-04"),
+						End:          newDate("2019-07-04"),
+						InvTypeCode:  "RoomType_1",
+						RatePlanCode: "RatePlan_1",
+					},
+					RestrictionStatus: &RestrictionStatus{Status: "Close", Restriction: "Arrival"},
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		printError(t, got, want)
+	}
+	checkEncode(t, "OTA_HotelAvailNotifRQ.xml", &got)
+}
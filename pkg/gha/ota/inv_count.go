@@ -0,0 +1,43 @@
+package ota
+
+import (
+	"encoding/xml"
+
+	"github.com/f-go/link/pkg/gha"
+)
+
+// Request that sets the number of rooms left of room types of a property.
+type HotelInvCountNotifRQ struct {
+	XMLName xml.Name `xml:"http://www.opentravel.org/OTA/2003/05 OTA_HotelInvCountNotifRQ"`
+	Header
+	Inventories Inventories
+}
+
+// Container for the inventory of a single property.
+type Inventories struct {
+	HotelCode string `xml:"HotelCode,attr"`
+	Inventory []Inventory
+}
+
+// Returns the property the inventory belongs to.
+func (i *Inventories) Property() gha.Property {
+	return gha.Property{ID: i.HotelCode}
+}
+
+// Inventory of a room type for a range of dates.
+type Inventory struct {
+	StatusApplicationControl StatusApplicationControl
+	InvCounts                []InvCount `xml:"InvCounts>InvCount"`
+}
+
+// Number of rooms left.
+type InvCount struct {
+	Count     int `xml:"Count,attr"`
+	CountType int `xml:"CountType,attr"` // 2 = definitive availability
+}
+
+// Response to a HotelInvCountNotifRQ.
+type HotelInvCountNotifRS struct {
+	XMLName xml.Name `xml:"http://www.opentravel.org/OTA/2003/05 OTA_HotelInvCountNotifRS"`
+	Response
+}
@@ -0,0 +1,33 @@
+package ota
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHotelInvCountNotifRQ(t *testing.T) {
+	var got HotelInvCountNotifRQ
+	readMessage(t, "OTA_HotelInvCountNotifRQ.xml", &got)
+
+	want := HotelInvCountNotifRQ{
+		XMLName: got.XMLName,
+		Header:  newHeader("2019-01-23T16:20:00-04:00"),
+		Inventories: Inventories{
+			HotelCode: "Hotel_1",
+			Inventory: []Inventory{
+				{
+					StatusApplicationControl: StatusApplicationControl{
+						Start:       newDate("2019-07-01"),
+						End:         newDate("2019-07-31"),
+						InvTypeCode: "RoomType_1",
+					},
+					InvCounts: []InvCount{{Count: 3, CountType: 2}},
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		printError(t, got, want)
+	}
+	checkEncode(t, "OTA_HotelInvCountNotifRQ.xml", &got)
+}
@@ -0,0 +1,151 @@
+// Package ota provides the OpenTravel messages of the ARI (availability,
+// rates and inventory) delivery mode, in which rates and availability are
+// pushed to Google instead of being returned for pull queries:
+// - OTA_HotelProductNotifRQ: room types and rate plans of a property.
+// - OTA_HotelRateAmountNotifRQ: rates of room types and rate plans.
+// - OTA_HotelAvailNotifRQ: availability and length of stay restrictions.
+// - OTA_HotelInvCountNotifRQ: number of rooms left.
+//
+// Google answers each request with the matching ...RS message, see Response.
+//
+// https://developers.google.com/hotels/hotel-prices/dev-guide/ari
+package ota
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/f-go/link/pkg/gha"
+)
+
+// Namespace of all OpenTravel messages.
+const Namespace = "http://www.opentravel.org/OTA/2003/05"
+
+// Version of the OpenTravel messages.
+const Version = "3.0"
+
+// Attributes and elements that all requests and responses start with.
+type Header struct {
+	EchoToken string       `xml:"EchoToken,attr,omitempty"`
+	TimeStamp gha.DateTime `xml:"TimeStamp,attr"`
+	Version   string       `xml:"Version,attr,omitempty"`
+	POS       *POS         `xml:",omitempty"`
+}
+
+// Returns the header of a new request of the given partner, with a random
+// echo token and the current time as time stamp.
+func NewHeader(partner string) Header {
+	return Header{
+		EchoToken: gha.NewTransactionID(),
+		TimeStamp: gha.DateTime(time.Now()),
+		Version:   Version,
+		POS:       NewPOS(partner),
+	}
+}
+
+// Container for the sender of a request.
+type POS struct {
+	Source Source
+}
+
+// Returns the point of sale of the given partner.
+func NewPOS(partner string) *POS {
+	return &POS{Source{RequestorID{ID: partner}}}
+}
+
+// Container for the ID of the sender.
+type Source struct {
+	RequestorID RequestorID
+}
+
+// The partner key of the sender.
+type RequestorID struct {
+	ID string `xml:"ID,attr"`
+}
+
+// Container for the dates, the room type and the rate plan a message
+// applies to. Start and End are inclusive.
+type StatusApplicationControl struct {
+	Start        gha.Date `xml:"Start,attr"`
+	End          gha.Date `xml:"End,attr"`
+	InvTypeCode  string   `xml:"InvTypeCode,attr,omitempty"`  // room type
+	RatePlanCode string   `xml:"RatePlanCode,attr,omitempty"` // rate plan
+}
+
+// Status of a processed request. A request is rejected if the response
+// contains errors; warnings do not prevent the request from being processed.
+type Response struct {
+	Header
+	Success  *struct{} `xml:",omitempty"`
+	Warnings *Warnings `xml:",omitempty"`
+	Errors   *Errors   `xml:",omitempty"`
+}
+
+// Container for the warnings of a response.
+type Warnings struct {
+	Warning []Message
+}
+
+// Container for the errors of a response.
+type Errors struct {
+	Error []Message
+}
+
+// Returns a *ResponseError if the response contains errors, otherwise nil.
+func (r *Response) Err() error {
+	if r.Errors == nil || len(r.Errors.Error) == 0 {
+		return nil
+	}
+	return &ResponseError{Errors: r.Errors.Error}
+}
+
+// Warning or error of a response.
+type Message struct {
+	Type      string `xml:"Type,attr,omitempty"`
+	Code      string `xml:"Code,attr,omitempty"`
+	ShortText string `xml:"ShortText,attr,omitempty"`
+	Text      string `xml:",chardata"`
+}
+
+func (m Message) String() string {
+	text := m.Text
+	if text == "" {
+		text = m.ShortText
+	}
+	return fmt.Sprintf("%s (type %s, code %s)", strings.TrimSpace(text), m.Type, m.Code)
+}
+
+// Error returned for a response that contains errors.
+type ResponseError struct {
+	Errors []Message
+}
+
+func (e *ResponseError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, m := range e.Errors {
+		msgs[i] = m.String()
+	}
+	return "ota: request rejected: " + strings.Join(msgs, "; ")
+}
+
+// Writes the given message, including the XML declaration.
+func Encode(w io.Writer, msg interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(msg); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// Reads a message into msg, which must point to one of the message types.
+func Decode(r io.Reader, msg interface{}) error {
+	return xml.NewDecoder(r).Decode(msg)
+}
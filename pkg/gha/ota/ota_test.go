@@ -0,0 +1,126 @@
+package ota
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/f-go/link/pkg/gha"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+func printError(t *testing.T, got, want interface{}) {
+	dmp := diffmatchpatch.New()
+	diffs := dmp.DiffMain(fmt.Sprintf("%+v", got), fmt.Sprintf("%+v", want), false)
+	t.Errorf("\ngot:  %+v\nwant: %+v\ndiff: %v", got, want, dmp.DiffPrettyText(diffs))
+}
+
+// Decodes the given file into msg.
+func readMessage(t *testing.T, file string, msg interface{}) {
+	f, err := os.Open("./testdata/" + file)
+	if err != nil {
+		t.Fatalf("File reading error %v", err)
+	}
+	defer f.Close()
+
+	if err := Decode(f, msg); err != nil {
+		t.Fatalf("Parsing file failed with error: %v", err)
+	}
+}
+
+// Checks that msg is encoded exactly like the given file.
+func checkEncode(t *testing.T, file string, msg interface{}) {
+	var buf bytes.Buffer
+	if err := Encode(&buf, msg); err != nil {
+		t.Fatalf("Encoding file failed with error: %v", err)
+	}
+	want, _ := ioutil.ReadFile("./testdata/" + file)
+	if buf.String() != string(want) {
+		t.Errorf("Encode got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func newHeader(timestamp string) Header {
+	ts, _ := gha.NewDateTime(timestamp)
+	return Header{
+		EchoToken: "12345678",
+		TimeStamp: ts,
+		Version:   Version,
+		POS:       NewPOS("partner_key"),
+	}
+}
+
+func newDate(value string) gha.Date {
+	d, _ := gha.NewDate(value)
+	return d
+}
+
+func newDecimal(value string) gha.Decimal {
+	d, _ := gha.ParseDecimal(value)
+	return d
+}
+
+func TestNewHeader(t *testing.T) {
+	h := NewHeader("partner_key")
+	if h.EchoToken == "" {
+		t.Errorf("EchoToken is empty")
+	}
+	if h.TimeStamp.IsZero() {
+		t.Errorf("TimeStamp is zero")
+	}
+	if h.Version != Version {
+		t.Errorf("Version got %q, want: %q", h.Version, Version)
+	}
+	if !reflect.DeepEqual(h.POS, NewPOS("partner_key")) {
+		printError(t, h.POS, NewPOS("partner_key"))
+	}
+}
+
+func TestResponseErrors(t *testing.T) {
+	var got HotelRateAmountNotifRS
+	readMessage(t, "OTA_HotelRateAmountNotifRS-Errors.xml", &got)
+
+	header := newHeader("2019-01-23T16:20:01-04:00")
+	header.POS = nil
+	want := HotelRateAmountNotifRS{
+		XMLName: got.XMLName,
+		Response: Response{
+			Header: header,
+			Warnings: &Warnings{Warning: []Message{
+				{Type: "3", Code: "392", Text: "Unknown rate plan"},
+			}},
+			Errors: &Errors{Error: []Message{
+				{Type: "12", Code: "450", ShortText: "Unable to process", Text: "Invalid currency code"},
+			}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		printError(t, got, want)
+	}
+
+	err := got.Err()
+	if _, ok := err.(*ResponseError); !ok {
+		t.Fatalf("Err got %v, want: *ResponseError", err)
+	}
+	if msg := err.Error(); !strings.Contains(msg, "Invalid currency code (type 12, code 450)") {
+		t.Errorf("Error got %q", msg)
+	}
+	checkEncode(t, "OTA_HotelRateAmountNotifRS-Errors.xml", &got)
+}
+
+func TestResponseSuccess(t *testing.T) {
+	var got HotelAvailNotifRS
+	readMessage(t, "OTA_HotelAvailNotifRS-Success.xml", &got)
+
+	if got.Success == nil {
+		t.Errorf("Success got nil")
+	}
+	if err := got.Err(); err != nil {
+		t.Errorf("Err got %v, want: nil", err)
+	}
+	checkEncode(t, "OTA_HotelAvailNotifRS-Success.xml", &got)
+}
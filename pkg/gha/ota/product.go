@@ -0,0 +1,70 @@
+package ota
+
+import (
+	"encoding/xml"
+
+	"github.com/f-go/link/pkg/gha"
+)
+
+// Request that defines the room types and rate plans of a property, which
+// rate, availability and inventory messages refer to by their codes.
+type HotelProductNotifRQ struct {
+	XMLName xml.Name `xml:"http://www.opentravel.org/OTA/2003/05 OTA_HotelProductNotifRQ"`
+	Header
+	HotelProducts HotelProducts
+}
+
+// Container for the products of a single property.
+type HotelProducts struct {
+	HotelCode    string `xml:"HotelCode,attr"`
+	HotelProduct []HotelProduct
+}
+
+// Returns the property the products belong to.
+func (p *HotelProducts) Property() gha.Property {
+	return gha.Property{ID: p.HotelCode}
+}
+
+// Container for room types and rate plans.
+type HotelProduct struct {
+	ProductNotifType string     `xml:"ProductNotifType,attr,omitempty"` // [New|Overlay|Remove]
+	RoomTypes        *RoomTypes `xml:",omitempty"`
+	RatePlans        *RatePlans `xml:",omitempty"`
+}
+
+// Container for room types.
+type RoomTypes struct {
+	RoomType []RoomType
+}
+
+// Container for rate plans.
+type RatePlans struct {
+	RatePlan []RatePlan
+}
+
+// Room type of a property.
+type RoomType struct {
+	Code        string             `xml:"Code,attr"`
+	Name        *gha.LocalizedText `xml:",omitempty"`
+	Description *gha.LocalizedText `xml:",omitempty"`
+	Occupancy   *Occupancy         `xml:",omitempty"`
+}
+
+// Max. number of guests of a room type.
+type Occupancy struct {
+	MaxOccupancy      uint8 `xml:"MaxOccupancy,attr"`
+	MaxChildOccupancy uint8 `xml:"MaxChildOccupancy,attr,omitempty"`
+}
+
+// Rate plan of a property.
+type RatePlan struct {
+	Code        string             `xml:"Code,attr"`
+	Name        *gha.LocalizedText `xml:",omitempty"`
+	Description *gha.LocalizedText `xml:",omitempty"`
+}
+
+// Response to a HotelProductNotifRQ.
+type HotelProductNotifRS struct {
+	XMLName xml.Name `xml:"http://www.opentravel.org/OTA/2003/05 OTA_HotelProductNotifRS"`
+	Response
+}
@@ -0,0 +1,54 @@
+package ota
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/f-go/link/pkg/gha"
+)
+
+func TestHotelProductNotifRQ(t *testing.T) {
+	var got HotelProductNotifRQ
+	readMessage(t, "OTA_HotelProductNotifRQ.xml", &got)
+
+	want := HotelProductNotifRQ{
+		XMLName: got.XMLName,
+		Header:  newHeader("2019-01-23T16:20:00-04:00"),
+		HotelProducts: HotelProducts{
+			HotelCode: "Hotel_1",
+			HotelProduct: []HotelProduct{
+				{
+					ProductNotifType: "New",
+					RoomTypes: &RoomTypes{RoomType: []RoomType{
+						{
+							Code: "RoomType_1",
+							Name: &gha.LocalizedText{Text: []gha.Text{
+								{Text: "Standard Double Room", Language: "en"},
+								{Text: "Doppelzimmer Standard", Language: "de"},
+							}},
+							Description: &gha.LocalizedText{Text: []gha.Text{
+								{Text: "A standard room with one double bed", Language: "en"},
+							}},
+							Occupancy: &Occupancy{MaxOccupancy: 3, MaxChildOccupancy: 1},
+						},
+					}},
+					RatePlans: &RatePlans{RatePlan: []RatePlan{
+						{
+							Code: "RatePlan_1",
+							Name: &gha.LocalizedText{Text: []gha.Text{
+								{Text: "Best Available Rate", Language: "en"},
+							}},
+						},
+					}},
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		printError(t, got, want)
+	}
+	if p := got.HotelProducts.Property(); p.ID != "Hotel_1" {
+		t.Errorf("Property got %v, want: Hotel_1", p.ID)
+	}
+	checkEncode(t, "OTA_HotelProductNotifRQ.xml", &got)
+}
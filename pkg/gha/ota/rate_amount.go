@@ -0,0 +1,83 @@
+package ota
+
+import (
+	"encoding/xml"
+
+	"github.com/f-go/link/pkg/gha"
+)
+
+// Request that sets the rates of room types and rate plans of a property.
+type HotelRateAmountNotifRQ struct {
+	XMLName xml.Name `xml:"http://www.opentravel.org/OTA/2003/05 OTA_HotelRateAmountNotifRQ"`
+	Header
+	NotifType          string `xml:"NotifType,attr,omitempty"` // [Overlay|Delta]
+	RateAmountMessages RateAmountMessages
+}
+
+// Container for the rates of a single property.
+type RateAmountMessages struct {
+	HotelCode         string `xml:"HotelCode,attr"`
+	RateAmountMessage []RateAmountMessage
+}
+
+// Returns the property the rates belong to.
+func (m *RateAmountMessages) Property() gha.Property {
+	return gha.Property{ID: m.HotelCode}
+}
+
+// Rates of a room type and rate plan for a range of dates.
+type RateAmountMessage struct {
+	StatusApplicationControl StatusApplicationControl
+	Rates                    []Rate `xml:"Rates>Rate"`
+}
+
+// Nightly rate by number of guests.
+type Rate struct {
+	BaseByGuestAmts        []BaseByGuestAmt        `xml:"BaseByGuestAmts>BaseByGuestAmt"`
+	AdditionalGuestAmounts *AdditionalGuestAmounts `xml:",omitempty"`
+}
+
+// Container for the amounts of additional guests.
+type AdditionalGuestAmounts struct {
+	AdditionalGuestAmount []AdditionalGuestAmount
+}
+
+// Nightly rate for the given number of guests. A rate without number of
+// guests applies to all occupancies.
+type BaseByGuestAmt struct {
+	AmountBeforeTax gha.Decimal  `xml:"AmountBeforeTax,attr"`
+	AmountAfterTax  *gha.Decimal `xml:"AmountAfterTax,attr,omitempty"`
+	CurrencyCode    string       `xml:"CurrencyCode,attr"` // ISO 4217, e.g. "USD"
+	NumberOfGuests  uint8        `xml:"NumberOfGuests,attr,omitempty"`
+}
+
+// Returns the amount before tax.
+func (a BaseByGuestAmt) BeforeTax() gha.Money {
+	return gha.Money{Value: a.AmountBeforeTax, Currency: a.CurrencyCode}
+}
+
+// Returns the amount after tax, or false if it is not set.
+func (a BaseByGuestAmt) AfterTax() (gha.Money, bool) {
+	if a.AmountAfterTax == nil {
+		return gha.Money{}, false
+	}
+	return gha.Money{Value: *a.AmountAfterTax, Currency: a.CurrencyCode}, true
+}
+
+// Nightly amount charged for each guest beyond the base occupancy.
+type AdditionalGuestAmount struct {
+	AgeQualifyingCode string      `xml:"AgeQualifyingCode,attr"` // [10 (adult)|8 (child)]
+	Amount            gha.Decimal `xml:"Amount,attr"`
+	CurrencyCode      string      `xml:"CurrencyCode,attr"` // ISO 4217, e.g. "USD"
+}
+
+// Returns the amount per additional guest.
+func (a AdditionalGuestAmount) Money() gha.Money {
+	return gha.Money{Value: a.Amount, Currency: a.CurrencyCode}
+}
+
+// Response to a HotelRateAmountNotifRQ.
+type HotelRateAmountNotifRS struct {
+	XMLName xml.Name `xml:"http://www.opentravel.org/OTA/2003/05 OTA_HotelRateAmountNotifRS"`
+	Response
+}
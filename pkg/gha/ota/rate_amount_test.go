@@ -0,0 +1,66 @@
+package ota
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/f-go/link/pkg/gha"
+)
+
+func TestHotelRateAmountNotifRQ(t *testing.T) {
+	var got HotelRateAmountNotifRQ
+	readMessage(t, "OTA_HotelRateAmountNotifRQ.xml", &got)
+
+	afterTax := newDecimal("110.00")
+	want := HotelRateAmountNotifRQ{
+		XMLName:   got.XMLName,
+		Header:    newHeader("2019-01-23T16:20:00-04:00"),
+		NotifType: "Overlay",
+		RateAmountMessages: RateAmountMessages{
+			HotelCode: "Hotel_1",
+			RateAmountMessage: []RateAmountMessage{
+				{
+					StatusApplicationControl: StatusApplicationControl{
+						Start:        newDate("2019-07-01"),
+						End:          newDate("2019-07-31"),
+						InvTypeCode:  "RoomType_1",
+						RatePlanCode: "RatePlan_1",
+					},
+					Rates: []Rate{
+						{
+							BaseByGuestAmts: []BaseByGuestAmt{
+								{AmountBeforeTax: newDecimal("100.00"), AmountAfterTax: &afterTax, CurrencyCode: "USD", NumberOfGuests: 1},
+								{AmountBeforeTax: newDecimal("120.00"), CurrencyCode: "USD", NumberOfGuests: 2},
+							},
+							AdditionalGuestAmounts: &AdditionalGuestAmounts{AdditionalGuestAmount: []AdditionalGuestAmount{
+								{AgeQualifyingCode: "10", Amount: newDecimal("25.00"), CurrencyCode: "USD"},
+							}},
+						},
+					},
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		printError(t, got, want)
+	}
+	checkEncode(t, "OTA_HotelRateAmountNotifRQ.xml", &got)
+}
+
+func TestBaseByGuestAmtMoney(t *testing.T) {
+	afterTax := newDecimal("110.00")
+	a := BaseByGuestAmt{AmountBeforeTax: newDecimal("100.00"), AmountAfterTax: &afterTax, CurrencyCode: "USD"}
+
+	if got, want := a.BeforeTax(), (gha.Money{Value: newDecimal("100.00"), Currency: "USD"}); got != want {
+		t.Errorf("BeforeTax got %v, want: %v", got, want)
+	}
+	got, ok := a.AfterTax()
+	if want := (gha.Money{Value: afterTax, Currency: "USD"}); !ok || got != want {
+		t.Errorf("AfterTax got %v, %v, want: %v, true", got, ok, want)
+	}
+
+	a.AmountAfterTax = nil
+	if _, ok := a.AfterTax(); ok {
+		t.Errorf("AfterTax got true, want: false")
+	}
+}
@@ -0,0 +1,158 @@
+package gha
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Number of guests of a rate that sets neither <Occupancy> nor
+// <OccupancyDetails>.
+const DefaultOccupancy = 2
+
+// Charge currency of a rate that does not set <ChargeCurrency>.
+const DefaultChargeCurrency = ChargeCurrencyWeb
+
+// Error returned if the price of a rate without <Baserate> is computed.
+var ErrNoBaserate = errors.New("gha: rate has no baserate")
+
+// Rules for computing prices. Use the same rules wherever a price is shown,
+// e.g. on the landing page and in the feed, so both show the same amounts.
+type PriceRules struct {
+	// Rounding mode of all amounts. Defaults to RoundHalfUp.
+	Mode RoundingMode
+
+	// Rounds <Baserate>, <Tax> and <OtherFees> to the minor units of the
+	// currency before they are added up, as a page does that lists them
+	// separately. Otherwise only the sums are rounded.
+	RoundComponents bool
+}
+
+// Price of a rate as shown to a user, in the currency of the rate. All
+// amounts are rounded to the minor units of the currency. ChargeCurrency only
+// tells when and where the user pays; it does not change any amount.
+type Price struct {
+	RateRuleID     string
+	Baserate       Money
+	Tax            Money
+	OtherFees      Money
+	Total          Money // Baserate + Tax + OtherFees for all nights
+	PerNight       Money // Total / Nights
+	PerGuest       Money // Total / Guests
+	Nights         int   // length of stay
+	Guests         int   // number of adults and children
+	ChargeCurrency ChargeCurrency
+}
+
+// Returns the price of the rate for a stay of the given number of nights.
+// <Baserate>, <Tax> and <OtherFees> are the amounts for the whole stay and
+// must be in the same currency. ChargeCurrency is that of the rate, or
+// DefaultChargeCurrency if it sets none.
+//
+// The rate must be fully resolved, i.e. one of the rates returned by
+// Result.EffectiveRates or RoomBundle.EffectiveRates.
+func (r Rate) Price(nights int, rules PriceRules) (Price, error) {
+	if r.Baserate == nil {
+		return Price{}, ErrNoBaserate
+	}
+	if nights < 1 {
+		return Price{}, fmt.Errorf("gha: length of stay must be at least 1 night, got %d", nights)
+	}
+
+	// Keeps the first error of the calculations below.
+	var err error
+	round := func(m Money) Money {
+		if err != nil {
+			return Money{}
+		}
+		var rounded Money
+		rounded, err = m.Round(rules.Mode)
+		return rounded
+	}
+
+	currency := r.Baserate.Currency
+	amount := func(m *Money) Money {
+		if m == nil {
+			return Money{NewDecimal(0, MinorUnits(currency)), currency}
+		}
+		if rules.RoundComponents {
+			return round(*m)
+		}
+		return *m
+	}
+
+	p := Price{
+		RateRuleID:     r.RateRuleID,
+		Nights:         nights,
+		Guests:         r.guests(),
+		ChargeCurrency: r.ChargeCurrency,
+	}
+	if p.ChargeCurrency == "" {
+		p.ChargeCurrency = DefaultChargeCurrency
+	}
+	base, tax, fees := amount(r.Baserate), amount(r.Tax), amount(r.OtherFees)
+	var total Money
+	if err == nil {
+		total, err = base.Add(tax)
+	}
+	if err == nil {
+		total, err = total.Add(fees)
+	}
+
+	p.Baserate = round(base)
+	p.Tax = round(tax)
+	p.OtherFees = round(fees)
+	p.Total = round(total)
+
+	units := MinorUnits(currency)
+	p.PerNight.Currency = currency
+	if err == nil {
+		p.PerNight.Value, err = p.Total.Value.Quo(int64(nights), units, rules.Mode)
+	}
+	if err == nil && p.Guests > 0 {
+		p.PerGuest.Currency = currency
+		p.PerGuest.Value, err = p.Total.Value.Quo(int64(p.Guests), units, rules.Mode)
+	}
+	if err != nil {
+		return Price{}, err
+	}
+	return p, nil
+}
+
+// Returns the number of guests of the rate, see DefaultOccupancy.
+func (r Rate) guests() int {
+	if r.OccupancyDetails != nil {
+		return r.OccupancyDetails.guests()
+	}
+	if r.Occupancy != 0 {
+		return int(r.Occupancy)
+	}
+	return DefaultOccupancy
+}
+
+// Returns the prices of all rates of the result that have a <Baserate>, in
+// the order of Result.EffectiveRates.
+func (r *Result) Prices(rules PriceRules) ([]Price, error) {
+	return prices(r.EffectiveRates(), int(r.Nights), rules)
+}
+
+// Returns the prices of all rates of the room bundle that have a <Baserate>,
+// in the order of RoomBundle.EffectiveRates. The room bundle is booked for
+// the nights of its result.
+func (b *RoomBundle) Prices(nights int, rules PriceRules) ([]Price, error) {
+	return prices(b.EffectiveRates(), nights, rules)
+}
+
+func prices(rates []Rate, nights int, rules PriceRules) ([]Price, error) {
+	var all []Price
+	for i, rate := range rates {
+		if rate.Baserate == nil {
+			continue
+		}
+		p, err := rate.Price(nights, rules)
+		if err != nil {
+			return nil, fmt.Errorf("rate %d: %w", i+1, err)
+		}
+		all = append(all, p)
+	}
+	return all, nil
+}
@@ -0,0 +1,107 @@
+package gha
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestResultPrices(t *testing.T) {
+	tx := readTransaction(t, "./testdata/Transaction-BaseRateAndConditionalRate.xml")
+
+	got, err := tx.Result[0].Prices(PriceRules{})
+	if err != nil {
+		t.Fatalf("Prices failed with error: %v", err)
+	}
+	want := []Price{
+		{
+			Baserate:       *money("200.00", "USD"),
+			Tax:            *money("20.00", "USD"),
+			OtherFees:      *money("1.00", "USD"),
+			Total:          *money("221.00", "USD"),
+			PerNight:       *money("221.00", "USD"),
+			PerGuest:       *money("110.50", "USD"),
+			Nights:         1,
+			Guests:         2,
+			ChargeCurrency: "web",
+		},
+		{
+			RateRuleID:     "mobile",
+			Baserate:       *money("180.00", "USD"),
+			Tax:            *money("18.00", "USD"),
+			OtherFees:      *money("1.00", "USD"),
+			Total:          *money("199.00", "USD"),
+			PerNight:       *money("199.00", "USD"),
+			PerGuest:       *money("99.50", "USD"),
+			Nights:         1,
+			Guests:         2,
+			ChargeCurrency: "web",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		printError(t, got, want)
+	}
+}
+
+func TestRatePrice(t *testing.T) {
+	rate := Rate{
+		Baserate:       money("100.005", "USD"),
+		Tax:            money("10.005", "USD"),
+		ChargeCurrency: "hotel",
+		OccupancyDetails: &OccupancyDetails{
+			NumAdults: 2,
+			Children:  &Children{Child: []Child{{8}}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		rules    PriceRules
+		total    string
+		perNight string
+		perGuest string
+	}{
+		{"Round sums half up", PriceRules{}, "110.01", "36.67", "36.67"},
+		{"Round sums down", PriceRules{Mode: RoundDown}, "110.01", "36.67", "36.67"},
+		{"Round components half up", PriceRules{RoundComponents: true}, "110.02", "36.67", "36.67"},
+		{"Round components half even", PriceRules{Mode: RoundHalfEven, RoundComponents: true}, "110.00", "36.67", "36.67"},
+		{"Round components up", PriceRules{Mode: RoundUp, RoundComponents: true}, "110.02", "36.68", "36.68"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := rate.Price(3, tt.rules)
+			if err != nil {
+				t.Fatalf("Price failed with error: %v", err)
+			}
+			if got := p.Total.Value.String(); got != tt.total {
+				t.Errorf("Total got %v, want: %v", got, tt.total)
+			}
+			if got := p.PerNight.Value.String(); got != tt.perNight {
+				t.Errorf("PerNight got %v, want: %v", got, tt.perNight)
+			}
+			if got := p.PerGuest.Value.String(); got != tt.perGuest {
+				t.Errorf("PerGuest got %v, want: %v", got, tt.perGuest)
+			}
+			if p.Guests != 3 || p.ChargeCurrency != "hotel" {
+				t.Errorf("Guests/ChargeCurrency got %v/%v, want: 3/hotel", p.Guests, p.ChargeCurrency)
+			}
+		})
+	}
+}
+
+func TestRatePriceErrors(t *testing.T) {
+	if _, err := (Rate{}).Price(1, PriceRules{}); err != ErrNoBaserate {
+		t.Errorf("Price without baserate got %v, want: %v", err, ErrNoBaserate)
+	}
+
+	rate := Rate{Baserate: money("100", "USD"), Tax: money("10", "EUR")}
+	if _, err := rate.Price(1, PriceRules{}); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Price with mixed currencies got %v, want: %v", err, ErrCurrencyMismatch)
+	}
+
+	rate = Rate{Baserate: money("100", "USD")}
+	if _, err := rate.Price(0, PriceRules{}); err == nil {
+		t.Errorf("Price for 0 nights did not fail")
+	}
+}
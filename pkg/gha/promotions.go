@@ -0,0 +1,355 @@
+package gha
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Promotions message that defines discounts on the rates of properties. A
+// promotion applies to a booking only if the booking meets all conditions
+// that are set.
+//
+// https://developers.google.com/hotels/hotel-prices/xml-reference/promotions
+type Promotions struct {
+	XMLName         xml.Name          `xml:"Promotions"`
+	Partner         string            `xml:"partner,attr"`
+	ID              string            `xml:"id,attr"`
+	Timestamp       DateTime          `xml:"timestamp,attr"`
+	HotelPromotions []HotelPromotions `xml:",omitempty"`
+}
+
+// Returns the promotions of the given property or nil if there are none.
+func (p *Promotions) Hotel(property Property) *HotelPromotions {
+	for i := range p.HotelPromotions {
+		if p.HotelPromotions[i].HotelID == property.ID {
+			return &p.HotelPromotions[i]
+		}
+	}
+	return nil
+}
+
+// Container for the promotions of a single property.
+type HotelPromotions struct {
+	HotelID   string      `xml:"hotel_id,attr"`
+	Promotion []Promotion `xml:",omitempty"`
+}
+
+// Definition of a promotion.
+//
+// * BookingDates / BookingWindow:
+//   The booking date must be in one of the ranges, and the check-in date
+//   between min. and max. days after the booking date.
+// * CheckinDates / StayDates:
+//   The check-in date must be in one of the ranges, and all nights or any
+//   night of the stay, see StayDates.
+// * LengthOfStay:
+//   Min. and max. number of nights of the stay.
+// * RoomTypes / RatePlans:
+//   The booked room and package, see Booking.
+// * Devices / UserCountries:
+//   The device and the country of the user.
+type Promotion struct {
+	ID            string         `xml:"id,attr"`
+	BookingDates  *DateRanges    `xml:",omitempty"`
+	BookingWindow *Window        `xml:",omitempty"`
+	CheckinDates  *DateRanges    `xml:",omitempty"`
+	Devices       *Devices       `xml:",omitempty"`
+	Discount      Discount       `xml:""`
+	LengthOfStay  *Window        `xml:",omitempty"`
+	RatePlans     *RatePlans     `xml:",omitempty"`
+	RoomTypes     *RoomTypes     `xml:",omitempty"`
+	StayDates     *StayDates     `xml:",omitempty"`
+	UserCountries *UserCountries `xml:",omitempty"`
+}
+
+// Container for one or more date ranges.
+type DateRanges struct {
+	DateRange []DateRange `xml:",omitempty"`
+}
+
+// Returns true if one of the ranges contains the date.
+func (r *DateRanges) Contains(d Date) bool {
+	for _, dr := range r.DateRange {
+		if dr.Contains(d) {
+			return true
+		}
+	}
+	return false
+}
+
+// Range of dates between start and end, inclusive. A range without start or
+// end is open on that side.
+//
+// DaysOfWeek restricts the range to the given days, e.g. "MTWHF" for
+// Monday to Friday:
+// M (Monday), T (Tuesday), W (Wednesday), H (Thursday), F (Friday),
+// S (Saturday), U (Sunday).
+type DateRange struct {
+	Start      Date   `xml:"start,attr"`
+	End        Date   `xml:"end,attr"`
+	DaysOfWeek string `xml:"days_of_week,attr,omitempty"`
+}
+
+// Returns true if the range contains the date.
+func (r DateRange) Contains(d Date) bool {
+	t := truncateDate(d)
+	if !r.Start.IsZero() && t.Before(truncateDate(r.Start)) {
+		return false
+	}
+	if !r.End.IsZero() && t.After(truncateDate(r.End)) {
+		return false
+	}
+	return r.DaysOfWeek == "" || strings.ContainsRune(strings.ToUpper(r.DaysOfWeek), weekdayCodes[t.Weekday()])
+}
+
+var weekdayCodes = [...]rune{
+	time.Sunday:    'U',
+	time.Monday:    'M',
+	time.Tuesday:   'T',
+	time.Wednesday: 'W',
+	time.Thursday:  'H',
+	time.Friday:    'F',
+	time.Saturday:  'S',
+}
+
+// Min. and max. number of days or nights, inclusive. Zero values stand for
+// no limit.
+type Window struct {
+	Min int `xml:"min,attr,omitempty"`
+	Max int `xml:"max,attr,omitempty"`
+}
+
+// Returns true if n is within the window.
+func (w *Window) Contains(n int) bool {
+	return (w.Min == 0 || n >= w.Min) && (w.Max == 0 || n <= w.Max)
+}
+
+// Devices the promotion is available on.
+type Devices struct {
+	Device []Device `xml:",omitempty"`
+}
+
+// A device type.
+type Device struct {
+	Type DeviceType `xml:"type,attr"` // [desktop|mobile|tablet]
+}
+
+// Countries the promotion is available in.
+type UserCountries struct {
+	Country []Country `xml:",omitempty"`
+}
+
+// A country.
+type Country struct {
+	Code string `xml:"code,attr"` // ISO 3166-1 alpha-2, e.g. "US"
+}
+
+// Packages the promotion applies to, by <PackageID>.
+type RatePlans struct {
+	RatePlan []IDRef `xml:",omitempty"`
+}
+
+// Rooms the promotion applies to, by <RoomID>.
+type RoomTypes struct {
+	RoomType []IDRef `xml:",omitempty"`
+}
+
+// Reference to a room or a package.
+type IDRef struct {
+	ID string `xml:"id,attr"`
+}
+
+// Stay dates of the promotion.
+//
+// * application:
+//   - all: Every night of the stay must be in one of the ranges (default).
+//   - any: At least one night of the stay must be in one of the ranges.
+type StayDates struct {
+	Application string `xml:"application,attr,omitempty"` // [all|any]
+	DateRanges
+}
+
+// Returns true if the nights of the stay meet the application of the dates.
+func (s *StayDates) Contains(checkin Date, nights int) bool {
+	first := truncateDate(checkin)
+	matched := 0
+	for i := 0; i < nights; i++ {
+		if s.DateRanges.Contains(Date(first.AddDate(0, 0, i))) {
+			matched++
+		}
+	}
+	if s.Application == "any" {
+		return matched > 0
+	}
+	return matched == nights
+}
+
+// Discount of a promotion, either a percentage of the base rate or a fixed
+// amount in the currency of the rate.
+//
+// * applied_nights:
+//   Number of nights the discount applies to, starting with the first night.
+//   If not set, the discount applies to all nights.
+// * application:
+//   - per_night: The fixed amount is deducted for each applied night
+//     (default).
+//   - per_stay: The fixed amount is deducted once.
+type Discount struct {
+	Percentage    *Decimal `xml:"percentage,attr,omitempty"`
+	FixedAmount   *Decimal `xml:"fixed_amount,attr,omitempty"`
+	AppliedNights int      `xml:"applied_nights,attr,omitempty"`
+	Application   string   `xml:"application,attr,omitempty"` // [per_night|per_stay]
+}
+
+// Returns the discount on the given base rate of a stay of n nights, rounded
+// to the minor units of its currency and at most the base rate.
+func (d Discount) Amount(baserate Money, nights int) (Money, error) {
+	applied := nights
+	if d.AppliedNights > 0 && d.AppliedNights < nights {
+		applied = d.AppliedNights
+	}
+
+	amount := Money{Currency: baserate.Currency}
+	var err error
+	switch {
+	case d.Percentage != nil:
+		amount.Value, err = mulQuo(baserate.Value, *d.Percentage, int64(applied), 100*int64(nights),
+			MinorUnits(baserate.Currency), RoundHalfUp)
+	case d.FixedAmount != nil && d.Application == "per_stay":
+		amount.Value = *d.FixedAmount
+	case d.FixedAmount != nil:
+		amount.Value, err = d.FixedAmount.Mul(NewDecimal(int64(applied), 0))
+	}
+	if err != nil {
+		return Money{}, err
+	}
+	if amount.Value.Cmp(baserate.Value) > 0 {
+		amount.Value = baserate.Value
+	}
+	return amount, nil
+}
+
+// Booking that promotions are applied to.
+type Booking struct {
+	Itinerary
+
+	RoomID    string      // <RoomID> of the booked room
+	PackageID string      // <PackageID> of the booked package, optional
+	Booked    Date        // booking date, in the local time of the hotel
+	User      UserContext // country and device of the user
+}
+
+// Returns true if the booking meets all conditions of the promotion.
+func (p *Promotion) Eligible(b Booking) bool {
+	if p.BookingDates != nil && !p.BookingDates.Contains(b.Booked) {
+		return false
+	}
+	advance := int(truncateDate(b.Checkin).Sub(truncateDate(b.Booked)) / day)
+	if p.BookingWindow != nil && !p.BookingWindow.Contains(advance) {
+		return false
+	}
+	if p.CheckinDates != nil && !p.CheckinDates.Contains(b.Checkin) {
+		return false
+	}
+	if p.StayDates != nil && !p.StayDates.Contains(b.Checkin, b.Nights) {
+		return false
+	}
+	if p.LengthOfStay != nil && !p.LengthOfStay.Contains(b.Nights) {
+		return false
+	}
+	if p.RoomTypes != nil && !containsID(p.RoomTypes.RoomType, b.RoomID) {
+		return false
+	}
+	if p.RatePlans != nil && !containsID(p.RatePlans.RatePlan, b.PackageID) {
+		return false
+	}
+	if p.Devices != nil && !containsDevice(p.Devices.Device, b.User.Device) {
+		return false
+	}
+	if p.UserCountries != nil && !containsCountry(p.UserCountries.Country, b.User.Country) {
+		return false
+	}
+	return true
+}
+
+func containsID(refs []IDRef, id string) bool {
+	for _, r := range refs {
+		if r.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func containsDevice(devices []Device, t DeviceType) bool {
+	for _, d := range devices {
+		if d.Type == t {
+			return true
+		}
+	}
+	return false
+}
+
+func containsCountry(countries []Country, code string) bool {
+	for _, c := range countries {
+		if strings.EqualFold(c.Code, code) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the given rate with the discount of the promotion deducted from its
+// <Baserate>, or false if the booking is not eligible or the rate has no
+// base rate. Taxes and fees are not changed.
+//
+// The returned rate does not share memory with the given rate.
+func (p *Promotion) Apply(rate Rate, b Booking) (Rate, bool, error) {
+	if rate.Baserate == nil || b.Nights < 1 || !p.Eligible(b) {
+		return rate, false, nil
+	}
+	discount, err := p.Discount.Amount(*rate.Baserate, b.Nights)
+	if err != nil {
+		return rate, false, fmt.Errorf("gha: promotion %q: %w", p.ID, err)
+	}
+	discounted := rate.Inherit(Rate{})
+	if discounted.Baserate.Value, err = discounted.Baserate.Value.Sub(discount.Value); err != nil {
+		return rate, false, fmt.Errorf("gha: promotion %q: %w", p.ID, err)
+	}
+	return discounted, true, nil
+}
+
+// Returns the given rate with the largest discount of all promotions the
+// booking is eligible for, together with that promotion, or the rate and nil
+// if the booking is eligible for none.
+func (h *HotelPromotions) Apply(rate Rate, b Booking) (Rate, *Promotion, error) {
+	best, promotion := rate, (*Promotion)(nil)
+	for i := range h.Promotion {
+		r, ok, err := h.Promotion[i].Apply(rate, b)
+		if err != nil {
+			return rate, nil, err
+		}
+		if ok && (promotion == nil || r.Baserate.Value.Cmp(best.Baserate.Value) < 0) {
+			best, promotion = r, &h.Promotion[i]
+		}
+	}
+	return best, promotion, nil
+}
+
+// Reads a Promotions message.
+func DecodePromotions(r io.Reader) (*Promotions, error) {
+	var p Promotions
+	if err := decodeFile(r, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Writes the given promotions as Promotions message, including the XML
+// declaration.
+func EncodePromotions(w io.Writer, p *Promotions) error {
+	return encodeFile(w, p)
+}
@@ -0,0 +1,222 @@
+package gha
+
+import (
+	"io"
+	"reflect"
+	"testing"
+)
+
+func readPromotions(t *testing.T) *Promotions {
+	var p *Promotions
+	readTestFile(t, "Promotions-Example.xml", func(r io.Reader) (err error) {
+		p, err = DecodePromotions(r)
+		return err
+	})
+	return p
+}
+
+func newDecimalPtr(value string) *Decimal {
+	d := MustParseDecimal(value)
+	return &d
+}
+
+func TestPromotionsFile(t *testing.T) {
+	got := readPromotions(t)
+
+	want := []HotelPromotions{
+		{
+			HotelID: "1234",
+			Promotion: []Promotion{
+				{
+					ID:            "early-bird",
+					BookingWindow: &Window{Min: 14},
+					CheckinDates: &DateRanges{DateRange: []DateRange{
+						{Start: newDate("2018-06-01"), End: newDate("2018-08-31"), DaysOfWeek: "MTWHF"},
+					}},
+					Discount:     Discount{Percentage: newDecimalPtr("10")},
+					LengthOfStay: &Window{Min: 2},
+					RoomTypes:    &RoomTypes{RoomType: []IDRef{{"RoomType101"}}},
+				},
+				{
+					ID: "mobile-us",
+					BookingDates: &DateRanges{DateRange: []DateRange{
+						{Start: newDate("2018-05-01"), End: newDate("2018-05-31")},
+					}},
+					Devices:   &Devices{Device: []Device{{DeviceMobile}, {DeviceTablet}}},
+					Discount:  Discount{FixedAmount: newDecimalPtr("20.00"), AppliedNights: 2},
+					RatePlans: &RatePlans{RatePlan: []IDRef{{"Breakfast"}}},
+					StayDates: &StayDates{
+						Application: "any",
+						DateRanges: DateRanges{DateRange: []DateRange{
+							{Start: newDate("2018-06-15"), End: newDate("2018-06-30")},
+						}},
+					},
+					UserCountries: &UserCountries{Country: []Country{{"US"}}},
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got.HotelPromotions, want) {
+		printError(t, got.HotelPromotions, want)
+	}
+	if got.Hotel(Property{"1234"}) != &got.HotelPromotions[0] {
+		t.Errorf("Hotel got %v, want: %v", got.Hotel(Property{"1234"}), &got.HotelPromotions[0])
+	}
+	if h := got.Hotel(Property{"5678"}); h != nil {
+		t.Errorf("Hotel got %v, want: nil", h)
+	}
+
+	checkEncodeFile(t, "Promotions-Example.xml", func(w io.Writer) error {
+		return EncodePromotions(w, got)
+	})
+}
+
+func TestDateRangeContains(t *testing.T) {
+	r := DateRange{Start: newDate("2018-06-01"), End: newDate("2018-06-30"), DaysOfWeek: "MTWHF"}
+
+	tests := []struct {
+		date string
+		want bool
+	}{
+		{"2018-05-31", false}, // before start
+		{"2018-06-01", true},  // Friday
+		{"2018-06-02", false}, // Saturday
+		{"2018-06-28", true},  // Thursday
+		{"2018-07-02", false}, // after end
+	}
+	for _, tt := range tests {
+		if got := r.Contains(newDate(tt.date)); got != tt.want {
+			t.Errorf("Contains(%s) got %v, want: %v", tt.date, got, tt.want)
+		}
+	}
+
+	open := DateRange{Start: newDate("2018-06-01")}
+	if !open.Contains(newDate("2030-01-01")) {
+		t.Errorf("Contains of open range got false, want: true")
+	}
+}
+
+func TestStayDatesContains(t *testing.T) {
+	s := StayDates{DateRanges: DateRanges{DateRange: []DateRange{
+		{Start: newDate("2018-06-15"), End: newDate("2018-06-30")},
+	}}}
+
+	if s.Contains(newDate("2018-06-13"), 3) {
+		t.Errorf("Contains with application all got true, want: false")
+	}
+	if !s.Contains(newDate("2018-06-15"), 3) {
+		t.Errorf("Contains with application all got false, want: true")
+	}
+	s.Application = "any"
+	if !s.Contains(newDate("2018-06-13"), 3) {
+		t.Errorf("Contains with application any got false, want: true")
+	}
+	if s.Contains(newDate("2018-06-12"), 3) {
+		t.Errorf("Contains with application any got true, want: false")
+	}
+}
+
+func TestDiscountAmount(t *testing.T) {
+	baserate := Money{MustParseDecimal("300.00"), "USD"}
+
+	tests := []struct {
+		name     string
+		discount Discount
+		nights   int
+		want     string
+	}{
+		{"Percentage", Discount{Percentage: newDecimalPtr("10")}, 3, "30.00"},
+		{"Percentage of applied nights", Discount{Percentage: newDecimalPtr("15"), AppliedNights: 1}, 3, "15.00"},
+		{"Fractional percentage", Discount{Percentage: newDecimalPtr("12.5"), AppliedNights: 2}, 3, "25.00"},
+		{"Fixed amount per night", Discount{FixedAmount: newDecimalPtr("20.00")}, 3, "60.00"},
+		{"Fixed amount of applied nights", Discount{FixedAmount: newDecimalPtr("20.00"), AppliedNights: 2}, 3, "40.00"},
+		{"Fixed amount per stay", Discount{FixedAmount: newDecimalPtr("20.00"), Application: "per_stay"}, 3, "20.00"},
+		{"Capped at base rate", Discount{FixedAmount: newDecimalPtr("150.00")}, 3, "300.00"},
+		{"Long percentage", Discount{Percentage: newDecimalPtr("33.333333")}, 3, "100.00"},
+		{"Long percentage of applied nights", Discount{Percentage: newDecimalPtr("33.333333"), AppliedNights: 1}, 7, "14.29"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.discount.Amount(baserate, tt.nights)
+			if err != nil {
+				t.Fatalf("Amount failed with error: %v", err)
+			}
+			if want := (Money{MustParseDecimal(tt.want), "USD"}); !got.Equal(want) {
+				t.Errorf("Amount got %v, want: %v", got, want)
+			}
+		})
+	}
+}
+
+func TestPromotionApply(t *testing.T) {
+	h := readPromotions(t).Hotel(Property{"1234"})
+	rate := Rate{
+		RateRuleID: "mobile",
+		Baserate:   &Money{MustParseDecimal("300.00"), "USD"},
+		Tax:        &Money{MustParseDecimal("30.00"), "USD"},
+	}
+	booking := Booking{
+		Itinerary: Itinerary{Checkin: newDate("2018-06-14"), Nights: 3}, // Thursday
+		RoomID:    "RoomType101",
+		PackageID: "Breakfast",
+		Booked:    newDate("2018-05-20"),
+		User:      UserContext{Country: "us", Device: DeviceMobile},
+	}
+
+	// Both promotions apply, the fixed amount of 2 x 20.00 beats 10%.
+	got, p, err := h.Apply(rate, booking)
+	if err != nil {
+		t.Fatalf("Apply failed with error: %v", err)
+	}
+	if p == nil || p.ID != "mobile-us" {
+		t.Fatalf("Apply got promotion %v, want: mobile-us", p)
+	}
+	want := Rate{
+		RateRuleID: "mobile",
+		Baserate:   &Money{MustParseDecimal("260.00"), "USD"},
+		Tax:        &Money{MustParseDecimal("30.00"), "USD"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		printError(t, got, want)
+	}
+	if !rate.Baserate.Equal(Money{MustParseDecimal("300.00"), "USD"}) {
+		t.Errorf("Apply modified the base rate: %v", rate.Baserate)
+	}
+
+	// Only the early bird promotion on desktop.
+	booking.User.Device = DeviceDesktop
+	got, p, err = h.Apply(rate, booking)
+	if err != nil {
+		t.Fatalf("Apply failed with error: %v", err)
+	}
+	if p == nil || p.ID != "early-bird" {
+		t.Fatalf("Apply got promotion %v, want: early-bird", p)
+	}
+	if !got.Baserate.Equal(Money{MustParseDecimal("270.00"), "USD"}) {
+		t.Errorf("Baserate got %v, want: 270.00 USD", got.Baserate)
+	}
+
+	tests := []struct {
+		name   string
+		change func(b *Booking)
+	}{
+		{"Booked too late", func(b *Booking) { b.Booked = newDate("2018-06-01") }},
+		{"Check-in on weekend", func(b *Booking) { b.Checkin = newDate("2018-06-16") }},
+		{"Too short", func(b *Booking) { b.Nights = 1 }},
+		{"Other room", func(b *Booking) { b.RoomID = "RoomType102" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := booking
+			tt.change(&b)
+			if got, p, err := h.Apply(rate, b); err != nil || p != nil || !reflect.DeepEqual(got, rate) {
+				t.Errorf("Apply got %v, %v, %v, want: no promotion", got, p, err)
+			}
+		})
+	}
+
+	if _, ok, _ := h.Promotion[0].Apply(Rate{}, booking); ok {
+		t.Errorf("Apply without base rate got true, want: false")
+	}
+}
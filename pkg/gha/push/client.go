@@ -0,0 +1,288 @@
+// Package push provides a client that delivers Transaction messages to Google
+// in the Push delivery mode.
+package push
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/f-go/link/pkg/gha"
+)
+
+// Max. size of a response body read by the client.
+const maxResponseSize = 1 << 20
+
+// Client that POSTs Transaction messages to a push endpoint.
+//
+// Failed deliveries are retried with backoff if the error is temporary, i.e.
+// on network errors, 429 Too Many Requests and 5xx responses. Rejections
+// reported in the feed status of the response are not retried.
+type Client struct {
+	// URL the messages are posted to.
+	Endpoint string
+
+	// HTTP client used for the requests. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+
+	// Adds the credentials of the partner to a request, optional.
+	Auth Authenticator
+
+	// Compresses the request body with gzip.
+	Gzip bool
+
+	// Max. number of retries of a failed delivery.
+	MaxRetries int
+
+	// Returns the time to wait before the given retry, starting at 1.
+	// Defaults to DefaultBackoff. A Retry-After header of the response takes
+	// precedence.
+	Backoff func(retry int) time.Duration
+}
+
+// Returns a new Client for the given endpoint that compresses messages and
+// retries a failed delivery up to three times.
+func NewClient(endpoint string) *Client {
+	return &Client{
+		Endpoint:   endpoint,
+		Gzip:       true,
+		MaxRetries: 3,
+	}
+}
+
+// Exponential backoff starting at one second, capped at one minute.
+func DefaultBackoff(retry int) time.Duration {
+	d := time.Second << uint(retry-1)
+	if d <= 0 || d > time.Minute {
+		d = time.Minute
+	}
+	return d
+}
+
+// Adds credentials to a push request.
+type Authenticator interface {
+	// Adds the credentials of the given partner to the request. The partner
+	// is the partner attribute of the Transaction that is sent.
+	Authenticate(req *http.Request, partner string) error
+}
+
+// Authenticator that sets static headers per partner, e.g. an Authorization
+// header. The headers for the empty partner key are used for partners that
+// have no headers of their own.
+type HeaderAuth map[string]http.Header
+
+// Error returned if no credentials are known for a partner.
+var ErrNoCredentials = errors.New("push: no credentials for partner")
+
+func (a HeaderAuth) Authenticate(req *http.Request, partner string) error {
+	h, ok := a[partner]
+	if !ok {
+		if h, ok = a[""]; !ok {
+			return fmt.Errorf("%w %q", ErrNoCredentials, partner)
+		}
+	}
+	for k, v := range h {
+		req.Header[k] = append([]string(nil), v...)
+	}
+	return nil
+}
+
+// Response of a successful delivery.
+type Response struct {
+	StatusCode int
+
+	// Feed status reported in the response body, nil if the body is empty
+	// or not a feed status.
+	Status *FeedStatus
+
+	Body []byte
+}
+
+// Feed status of a delivered message. A message is rejected if the status
+// contains errors; warnings do not prevent the message from being processed.
+type FeedStatus struct {
+	Success  *struct{} `xml:",omitempty"`
+	Warnings []Message `xml:"Warnings>Warning,omitempty"`
+	Errors   []Message `xml:"Errors>Error,omitempty"`
+}
+
+// Warning or error of a feed status.
+type Message struct {
+	Type string `xml:"type,attr,omitempty"`
+	Code string `xml:"code,attr,omitempty"`
+	Text string `xml:",chardata"`
+}
+
+// Error returned if a message has not been accepted.
+type StatusError struct {
+	StatusCode int
+	Status     *FeedStatus
+	Body       []byte
+}
+
+func (e *StatusError) Error() string {
+	if e.Status != nil && len(e.Status.Errors) > 0 {
+		m := e.Status.Errors[0]
+		return fmt.Sprintf("push: message rejected with status %d: %s (code %s)", e.StatusCode, m.Text, m.Code)
+	}
+	return fmt.Sprintf("push: message rejected with status %d: %s", e.StatusCode, bytes.TrimSpace(e.Body))
+}
+
+// Returns true if the delivery may succeed when retried.
+func (e *StatusError) Temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// Sends the given Transaction message. The returned error is a *StatusError
+// if the message has been rejected.
+func (c *Client) Send(ctx context.Context, t *gha.Transaction) (*Response, error) {
+	body, err := c.encode(t)
+	if err != nil {
+		return nil, err
+	}
+
+	backoff := c.Backoff
+	if backoff == nil {
+		backoff = DefaultBackoff
+	}
+
+	for retry := 0; ; retry++ {
+		resp, wait, err := c.send(ctx, body, t.Partner)
+		if err == nil || retry >= c.MaxRetries || !temporary(err) || ctx.Err() != nil {
+			return resp, err
+		}
+
+		if wait == 0 {
+			wait = backoff(retry + 1)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Returns the request body for the given message.
+func (c *Client) encode(t *gha.Transaction) ([]byte, error) {
+	data, err := xml.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	var w io.Writer = &buf
+	var zw *gzip.Writer
+	if c.Gzip {
+		zw = gzip.NewWriter(&buf)
+		w = zw
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if zw != nil {
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// Makes a single delivery attempt. It returns the wait time requested by a
+// Retry-After header, if any.
+func (c *Client) send(ctx context.Context, body []byte, partner string) (*Response, time.Duration, error) {
+	req, err := http.NewRequest(http.MethodPost, c.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, 0, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+	if c.Gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	if c.Auth != nil {
+		if err := c.Auth.Authenticate(req, partner); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, 0, err
+	}
+	status := parseFeedStatus(data)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 || (status != nil && len(status.Errors) > 0) {
+		return nil, retryAfter(resp), &StatusError{
+			StatusCode: resp.StatusCode,
+			Status:     status,
+			Body:       data,
+		}
+	}
+
+	return &Response{
+		StatusCode: resp.StatusCode,
+		Status:     status,
+		Body:       data,
+	}, 0, nil
+}
+
+// Returns the feed status of the given response body or nil if it has none.
+func parseFeedStatus(data []byte) *FeedStatus {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+	var s FeedStatus
+	if err := xml.Unmarshal(data, &s); err != nil {
+		return nil
+	}
+	if s.Success == nil && len(s.Warnings) == 0 && len(s.Errors) == 0 {
+		return nil
+	}
+	return &s
+}
+
+// Returns the wait time of the Retry-After header given in seconds.
+func retryAfter(resp *http.Response) time.Duration {
+	s, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || s < 0 {
+		return 0
+	}
+	return time.Duration(s) * time.Second
+}
+
+// Returns true if a delivery that failed with the given error may succeed
+// when retried.
+func temporary(err error) bool {
+	var se *StatusError
+	if errors.As(err, &se) {
+		return se.Temporary()
+	}
+	// transport errors, but not a malformed endpoint
+	var ue *url.Error
+	return errors.As(err, &ue) && ue.Op != "parse"
+}
@@ -0,0 +1,240 @@
+package push
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/f-go/link/pkg/gha"
+)
+
+func newTestTransaction() *gha.Transaction {
+	timestamp, _ := gha.NewDateTime("2017-07-23T16:20:00-04:00")
+	checkin, _ := gha.NewDate("2018-06-10")
+	return &gha.Transaction{
+		ID:        "42",
+		Timestamp: timestamp,
+		Partner:   "partner",
+		Result: []gha.Result{
+			{
+				Property: gha.Property{ID: "060773"},
+				Checkin:  checkin,
+				Nights:   2,
+			},
+		},
+	}
+}
+
+func noBackoff(int) time.Duration {
+	return 0
+}
+
+func TestClientSend(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Method got %v, want: POST", r.Method)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization got %v, want: Bearer secret", got)
+		}
+		if got := r.Header.Get("Content-Encoding"); got != "gzip" {
+			t.Errorf("Content-Encoding got %v, want: gzip", got)
+		}
+
+		// t.Fatalf must not be called outside of the test goroutine.
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			t.Errorf("Reading gzip body failed with error: %v", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var got gha.Transaction
+		if err := xml.NewDecoder(zr).Decode(&got); err != nil {
+			t.Errorf("Parsing request failed with error: %v", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if got.ID != "42" || len(got.Result) != 1 || got.Result[0].Property.ID != "060773" {
+			t.Errorf("Transaction got %+v", got)
+		}
+
+		w.Write([]byte(`<Response><Success/><Warnings><Warning type="3" code="12">Missing tax</Warning></Warnings></Response>`))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL)
+	c.Auth = HeaderAuth{
+		"partner": http.Header{"Authorization": {"Bearer secret"}},
+	}
+
+	resp, err := c.Send(context.Background(), newTestTransaction())
+	if err != nil {
+		t.Fatalf("Send failed with error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("StatusCode got %v, want: %v", resp.StatusCode, http.StatusOK)
+	}
+	if resp.Status == nil || resp.Status.Success == nil {
+		t.Fatalf("Status got %+v, want success", resp.Status)
+	}
+	if len(resp.Status.Warnings) != 1 || resp.Status.Warnings[0] != (Message{"3", "12", "Missing tax"}) {
+		t.Errorf("Warnings got %+v", resp.Status.Warnings)
+	}
+}
+
+func TestClientSendRetries(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if len(body) == 0 {
+			t.Errorf("Retry has an empty body")
+		}
+		if atomic.AddInt32(&calls, 1) < 3 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL)
+	c.Gzip = false
+	c.Backoff = noBackoff
+
+	resp, err := c.Send(context.Background(), newTestTransaction())
+	if err != nil {
+		t.Fatalf("Send failed with error: %v", err)
+	}
+	if resp.Status != nil {
+		t.Errorf("Status got %+v, want: nil", resp.Status)
+	}
+	if calls != 3 {
+		t.Errorf("Calls got %v, want: 3", calls)
+	}
+}
+
+func TestClientSendErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		handler   http.HandlerFunc
+		auth      Authenticator
+		wantCalls int32
+		check     func(t *testing.T, err error)
+	}{
+		{
+			"Retries exhausted",
+			func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "too many requests", http.StatusTooManyRequests)
+			},
+			nil,
+			3,
+			func(t *testing.T, err error) {
+				var se *StatusError
+				if !errors.As(err, &se) || se.StatusCode != http.StatusTooManyRequests {
+					t.Errorf("Error got %v, want: status 429", err)
+				}
+			},
+		},
+		{
+			"Rejected by feed status",
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`<Response><Errors><Error type="3" code="1001">Invalid timestamp</Error></Errors></Response>`))
+			},
+			nil,
+			1,
+			func(t *testing.T, err error) {
+				var se *StatusError
+				if !errors.As(err, &se) || se.Status == nil || se.Status.Errors[0].Code != "1001" {
+					t.Errorf("Error got %v, want: error code 1001", err)
+				}
+			},
+		},
+		{
+			"Bad request",
+			func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "bad request", http.StatusBadRequest)
+			},
+			nil,
+			1,
+			func(t *testing.T, err error) {
+				if want := "push: message rejected with status 400: bad request"; err == nil || err.Error() != want {
+					t.Errorf("Error got %v, want: %v", err, want)
+				}
+			},
+		},
+		{
+			"Unknown partner",
+			func(w http.ResponseWriter, r *http.Request) {},
+			HeaderAuth{"other": http.Header{}},
+			0,
+			func(t *testing.T, err error) {
+				if !errors.Is(err, ErrNoCredentials) {
+					t.Errorf("Error got %v, want: %v", err, ErrNoCredentials)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+				tt.handler(w, r)
+			}))
+			defer server.Close()
+
+			c := NewClient(server.URL)
+			c.MaxRetries = 2
+			c.Backoff = noBackoff
+			c.Auth = tt.auth
+
+			resp, err := c.Send(context.Background(), newTestTransaction())
+			if resp != nil {
+				t.Errorf("Response got %+v, want: nil", resp)
+			}
+			tt.check(t, err)
+			if calls != tt.wantCalls {
+				t.Errorf("Calls got %v, want: %v", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestClientSendCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	c := NewClient(server.URL)
+	c.Backoff = func(int) time.Duration { return time.Hour }
+
+	if _, err := c.Send(ctx, newTestTransaction()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Error got %v, want: %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestDefaultBackoff(t *testing.T) {
+	for retry, want := range map[int]time.Duration{
+		1:  time.Second,
+		2:  2 * time.Second,
+		4:  8 * time.Second,
+		7:  time.Minute,
+		70: time.Minute,
+	} {
+		if got := DefaultBackoff(retry); got != want {
+			t.Errorf("DefaultBackoff(%d) got %v, want: %v", retry, got, want)
+		}
+	}
+}
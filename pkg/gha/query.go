@@ -1,9 +1,5 @@
 package gha
 
-import (
-	cdt "github.com/f-go/go-custom-datetime"
-)
-
 // Query messages are requests from Google for pricing or
 // metadata updates. They are used with both the Pull and
 // Pull with Hints delivery modes.
@@ -17,7 +13,9 @@ import (
 //   pricing information in <Result> elements.
 //
 //   Live Queries are a special type of pricing Query message in
-//   which Google asks for real-time price updates.
+//   which Google asks for real-time price updates. They are flagged
+//   with <LatencySensitive> and describe the guests and the market of
+//   the user in a <Context> element.
 //
 //   For more information, consult Pricing Overview.
 //
@@ -32,15 +30,31 @@ import (
 // The syntax for the messages is different, depending on the type. Both
 // types are described in this section.
 type Query struct {
-	Checkin             cdt.CustomDate       `xml:",omitempty"`
+	Checkin             Date                 `xml:",omitempty"`
 	Nights              int                  `xml:",omitempty"`
+	LatencySensitive    bool                 `xml:",omitempty"`
 	PropertyList        *PropertyList        `xml:",omitempty"`
+	Context             *Context             `xml:",omitempty"`
 	HotelInfoProperties *HotelInfoProperties `xml:",omitempty"`
+}
 
-	// Left out Live Query support (<LatencySensitive> and <Context>) for now.
-	// This will be implemented later.
-	//
-	// https://developers.google.com/hotels/hotel-prices/xml-reference/queries#Context
+// Returns the itineraries Google asks prices for. The itineraries of the
+// <Context> take precedence over the <Checkin> and <Nights> of the query.
+// Metadata queries have no itineraries.
+func (q *Query) Itineraries() []Itinerary {
+	if q.Context != nil && len(q.Context.Itinerary) > 0 {
+		return q.Context.Itinerary
+	}
+	if q.Nights == 0 {
+		return nil
+	}
+	return []Itinerary{{Checkin: q.Checkin, Nights: q.Nights}}
+}
+
+// Returns true for a Live Query, i.e. a pricing query that is flagged with
+// <LatencySensitive> or carries a user <Context>.
+func (q *Query) IsLive() bool {
+	return q.LatencySensitive || q.Context != nil
 }
 
 // One or more IDs for hotel that require pricing updates.
@@ -54,3 +68,23 @@ type PropertyList struct {
 type HotelInfoProperties struct {
 	Property []Property `xml:",omitempty"`
 }
+
+// Container for the user context of a Live Query. Google sends it to ask
+// prices for a specific guest mix, user country and device. The prices
+// returned for this query must be valid for the given context.
+//
+// https://developers.google.com/hotels/hotel-prices/xml-reference/queries#Context
+type Context struct {
+	Occupancy        uint8             `xml:",omitempty"`
+	OccupancyDetails *OccupancyDetails `xml:",omitempty"`
+	UserCountry      string            `xml:",omitempty"` // ISO 3166-1 alpha-2, e.g. "US"
+	UserDevice       DeviceType        `xml:",omitempty"` // [desktop|mobile|tablet]
+	Itinerary        []Itinerary       `xml:",omitempty"`
+}
+
+// A single itinerary of a Live Query <Context>. A context can contain
+// several itineraries that share the same guests and user market.
+type Itinerary struct {
+	Checkin Date `xml:""`
+	Nights  int  `xml:""`
+}
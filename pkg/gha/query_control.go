@@ -0,0 +1,119 @@
+package gha
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Query control message that configures how Google queries your server: if
+// it sends Live Queries, how many properties and itineraries a single query
+// may ask for, and whether Hint Response items may group several properties.
+//
+// * MultipleItineraries:
+//   - checkin_range:  A check-in range <Item> may list more than one property.
+//   - affected_dates: A ranged stay <Item> may list more than one property.
+//   If not set, both item types list a single property.
+// * MaxProperties:
+//   Max. number of properties in a query and in an <Item>. Exact itinerary
+//   items never exceed MaxExactItemProperties.
+// * MaxItineraries:
+//   Max. number of itineraries in a query. A check-in range <Item> covers at
+//   most that many check-in dates.
+//
+// A nil *QueryControl stands for the defaults of all options.
+type QueryControl struct {
+	XMLName             xml.Name            `xml:"QueryControl"`
+	MultipleItineraries MultipleItineraries `xml:",omitempty"` // [checkin_range|affected_dates]
+	LiveQueries         bool                `xml:",omitempty"` // Google sends Live Queries
+	LatencySensitive    bool                `xml:",omitempty"` // Live Queries may be latency sensitive
+	MaxProperties       int                 `xml:",omitempty"` // 0 means no limit
+	MaxItineraries      int                 `xml:",omitempty"` // 0 means no limit
+}
+
+// Returns the max. number of properties in an exact itinerary <Item>.
+func (c *QueryControl) ExactItemProperties() int {
+	return c.itemProperties(true)
+}
+
+// Returns the max. number of properties in a check-in range <Item>.
+func (c *QueryControl) CheckinRangeItemProperties() int {
+	return c.itemProperties(c != nil && c.MultipleItineraries == MultipleItinerariesCheckinRange)
+}
+
+// Returns the max. number of properties in a ranged stay <Item>.
+func (c *QueryControl) RangedStayItemProperties() int {
+	return c.itemProperties(c != nil && c.MultipleItineraries == MultipleItinerariesAffectedDates)
+}
+
+func (c *QueryControl) itemProperties(multiple bool) int {
+	if !multiple {
+		return 1
+	}
+	if c != nil && c.MaxProperties > 0 && c.MaxProperties < MaxExactItemProperties {
+		return c.MaxProperties
+	}
+	return MaxExactItemProperties
+}
+
+// Returns the max. number of check-in dates of a check-in range <Item>, or
+// 0 if there is no limit.
+func (c *QueryControl) CheckinRangeDays() int {
+	if c == nil {
+		return 0
+	}
+	return c.MaxItineraries
+}
+
+// Returns an error if the given query asks for more properties or
+// itineraries than the options allow.
+func (c *QueryControl) Accepts(q *Query) error {
+	if c == nil {
+		return nil
+	}
+	properties := 0
+	if q.PropertyList != nil {
+		properties = len(q.PropertyList.Property)
+	} else if q.HotelInfoProperties != nil {
+		properties = len(q.HotelInfoProperties.Property)
+	}
+	if c.MaxProperties > 0 && properties > c.MaxProperties {
+		return fmt.Errorf("gha: query has %d properties, max. %d", properties, c.MaxProperties)
+	}
+	if n := len(q.Itineraries()); c.MaxItineraries > 0 && n > c.MaxItineraries {
+		return fmt.Errorf("gha: query has %d itineraries, max. %d", n, c.MaxItineraries)
+	}
+	return nil
+}
+
+// Returns an error if the given query is a Live Query or latency sensitive
+// although the options disable it. The options tell Google what to send;
+// a query that Google sends anyway can usually still be answered.
+func (c *QueryControl) Enables(q *Query) error {
+	if c == nil {
+		return nil
+	}
+	if q.IsLive() && !c.LiveQueries {
+		return errors.New("gha: live queries are disabled")
+	}
+	if q.LatencySensitive && !c.LatencySensitive {
+		return errors.New("gha: latency sensitive queries are disabled")
+	}
+	return nil
+}
+
+// Reads a query control message.
+func DecodeQueryControl(r io.Reader) (*QueryControl, error) {
+	var c QueryControl
+	if err := decodeFile(r, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Writes the given options as query control message, including the XML
+// declaration.
+func EncodeQueryControl(w io.Writer, c *QueryControl) error {
+	return encodeFile(w, c)
+}
@@ -0,0 +1,130 @@
+package gha
+
+import (
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestQueryControlFile(t *testing.T) {
+	var got *QueryControl
+	readTestFile(t, "QueryControl-Example.xml", func(r io.Reader) (err error) {
+		got, err = DecodeQueryControl(r)
+		return err
+	})
+
+	want := &QueryControl{
+		XMLName:             got.XMLName,
+		MultipleItineraries: MultipleItinerariesCheckinRange,
+		LiveQueries:         true,
+		MaxProperties:       50,
+		MaxItineraries:      14,
+	}
+	if !reflect.DeepEqual(got, want) {
+		printError(t, got, want)
+	}
+
+	checkEncodeFile(t, "QueryControl-Example.xml", func(w io.Writer) error {
+		return EncodeQueryControl(w, got)
+	})
+}
+
+func TestQueryControlItemProperties(t *testing.T) {
+	tests := []struct {
+		name                            string
+		control                         *QueryControl
+		exact, checkinRange, rangedStay int
+	}{
+		{"Defaults", nil, 100, 1, 1},
+		{"Check-in range", &QueryControl{MultipleItineraries: MultipleItinerariesCheckinRange}, 100, 100, 1},
+		{"Affected dates", &QueryControl{MultipleItineraries: MultipleItinerariesAffectedDates, MaxProperties: 20}, 20, 1, 20},
+		{"Limit above max.", &QueryControl{MaxProperties: 500}, 100, 1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if n := tt.control.ExactItemProperties(); n != tt.exact {
+				t.Errorf("ExactItemProperties got %v, want: %v", n, tt.exact)
+			}
+			if n := tt.control.CheckinRangeItemProperties(); n != tt.checkinRange {
+				t.Errorf("CheckinRangeItemProperties got %v, want: %v", n, tt.checkinRange)
+			}
+			if n := tt.control.RangedStayItemProperties(); n != tt.rangedStay {
+				t.Errorf("RangedStayItemProperties got %v, want: %v", n, tt.rangedStay)
+			}
+		})
+	}
+}
+
+func TestQueryControlAccepts(t *testing.T) {
+	pricing := &Query{
+		Checkin:      newDate("2018-06-10"),
+		Nights:       3,
+		PropertyList: &PropertyList{Property: []Property{{"pid5"}, {"pid8"}}},
+	}
+	live := &Query{
+		LatencySensitive: true,
+		PropertyList:     pricing.PropertyList,
+		Context: &Context{
+			Itinerary: []Itinerary{
+				{newDate("2018-06-10"), 3},
+				{newDate("2018-06-11"), 2},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		control *QueryControl
+		query   *Query
+		ok      bool
+	}{
+		{"No options", nil, live, true},
+		{"Pricing query", &QueryControl{}, pricing, true},
+		{"Live queries disabled", &QueryControl{}, live, true},
+		{"Too many properties", &QueryControl{MaxProperties: 1}, pricing, false},
+		{"Too many itineraries", &QueryControl{LiveQueries: true, LatencySensitive: true, MaxItineraries: 1}, live, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.control.Accepts(tt.query); (err == nil) != tt.ok {
+				t.Errorf("Accepts got %v, want ok: %v", err, tt.ok)
+			}
+		})
+	}
+}
+
+func TestQueryControlEnables(t *testing.T) {
+	pricing := &Query{
+		Checkin:      newDate("2018-06-10"),
+		Nights:       3,
+		PropertyList: &PropertyList{Property: []Property{{"pid5"}}},
+	}
+	live := &Query{
+		LatencySensitive: true,
+		PropertyList:     pricing.PropertyList,
+		Context:          &Context{Itinerary: []Itinerary{{newDate("2018-06-10"), 3}}},
+	}
+
+	tests := []struct {
+		name    string
+		control *QueryControl
+		query   *Query
+		ok      bool
+	}{
+		{"No options", nil, live, true},
+		{"Pricing query", &QueryControl{}, pricing, true},
+		{"Live queries disabled", &QueryControl{}, live, false},
+		{"Latency sensitive disabled", &QueryControl{LiveQueries: true}, live, false},
+		{"Live query", &QueryControl{LiveQueries: true, LatencySensitive: true}, live, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.control.Enables(tt.query); (err == nil) != tt.ok {
+				t.Errorf("Enables got %v, want ok: %v", err, tt.ok)
+			}
+		})
+	}
+}
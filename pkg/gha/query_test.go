@@ -7,7 +7,6 @@ import (
 	"reflect"
 	"testing"
 
-	cdt "github.com/f-go/go-custom-datetime"
 	"github.com/sergi/go-diff/diffmatchpatch"
 )
 
@@ -25,7 +24,7 @@ func TestPricingQuery(t *testing.T) {
 		return
 	}
 
-	checkin, _ := cdt.NewCustomDate("2018-06-10")
+	checkin, _ := NewDate("2018-06-10")
 	want = Query{
 		Checkin: checkin,
 		Nights:  3,
@@ -76,3 +75,94 @@ func TestMetadataQuery(t *testing.T) {
 		t.Errorf("Query\ngot:  %v\nwant: %v\ndiff: %v", got, want, dmp.DiffPrettyText(diffs))
 	}
 }
+
+func TestLiveQuery(t *testing.T) {
+	var got, want Query
+
+	request, err := ioutil.ReadFile("./testdata/Query-LiveQuery.xml")
+	if err != nil {
+		t.Errorf("File reading error %v", err)
+		return
+	}
+	if err = xml.Unmarshal(request, &got); err != nil {
+		t.Errorf("Parsing request data failed with error: %v", err)
+		return
+	}
+
+	want = Query{
+		LatencySensitive: true,
+		PropertyList: &PropertyList{
+			Property: []Property{
+				{"pid5"},
+				{"pid8"},
+			},
+		},
+		Context: &Context{
+			Occupancy: 3,
+			OccupancyDetails: &OccupancyDetails{
+				NumAdults: 2,
+				Children: &Children{
+					Child: []Child{
+						{4},
+					},
+				},
+			},
+			UserCountry: "US",
+			UserDevice:  "mobile",
+			Itinerary: []Itinerary{
+				{newDate("2018-06-10"), 3},
+				{newDate("2018-06-11"), 2},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		dmp := diffmatchpatch.New()
+		diffs := dmp.DiffMain(fmt.Sprintf("%v", got), fmt.Sprintf("%v", want), false)
+		t.Errorf("Query\ngot:  %v\nwant: %v\ndiff: %v", got, want, dmp.DiffPrettyText(diffs))
+	}
+}
+
+func TestQueryItineraries(t *testing.T) {
+	tests := []struct {
+		name  string
+		query Query
+		want  []Itinerary
+	}{
+		{
+			"Metadata query",
+			Query{HotelInfoProperties: &HotelInfoProperties{}},
+			nil,
+		},
+		{
+			"Pricing query",
+			Query{Checkin: newDate("2018-06-10"), Nights: 3},
+			[]Itinerary{{newDate("2018-06-10"), 3}},
+		},
+		{
+			"Live query with itineraries in context",
+			Query{
+				Checkin: newDate("2018-06-10"),
+				Nights:  3,
+				Context: &Context{
+					Itinerary: []Itinerary{
+						{newDate("2018-06-11"), 1},
+						{newDate("2018-06-12"), 2},
+					},
+				},
+			},
+			[]Itinerary{
+				{newDate("2018-06-11"), 1},
+				{newDate("2018-06-12"), 2},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.query.Itineraries(); !reflect.DeepEqual(got, tt.want) {
+				printError(t, got, tt.want)
+			}
+		})
+	}
+}
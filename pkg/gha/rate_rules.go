@@ -0,0 +1,167 @@
+package gha
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// Rate Rules file that defines the conditions of conditional rates. A
+// <Rate> refers to a definition with its rate_rule_id attribute.
+//
+// https://developers.google.com/hotels/hotel-prices/dev-guide/conditional-rates
+type RateRuleDefinitions struct {
+	XMLName            xml.Name             `xml:"RateRuleDefinitions"`
+	RateRuleDefinition []RateRuleDefinition `xml:",omitempty"`
+}
+
+// Definition of a rate rule. A rate with this rule is only shown to users
+// who meet all conditions that are set.
+//
+// * Private:
+//   The rate is not publicly available and only shown to signed-in users.
+// * Member:
+//   The rate is only available to members of the loyalty program.
+// * RateQualifiesFor:
+//   The rate is only available to users who qualify for all of the given
+//   qualifications, e.g. "senior" or "government".
+type RateRuleDefinition struct {
+	ID               string            `xml:"id,attr"`
+	Description      string            `xml:",omitempty"`
+	Private          bool              `xml:",omitempty"`
+	Member           bool              `xml:",omitempty"`
+	UserCountryCodes *UserCountryCodes `xml:",omitempty"`
+	UserDeviceTypes  *UserDeviceTypes  `xml:",omitempty"`
+	RateQualifiesFor *RateQualifiesFor `xml:",omitempty"`
+}
+
+// Countries the rate is available in (type "include") or not available in
+// (type "exclude").
+type UserCountryCodes struct {
+	Type            string   `xml:"type,attr"`  // [include|exclude]
+	UserCountryCode []string `xml:",omitempty"` // ISO 3166-1 alpha-2, e.g. "US"
+}
+
+// Devices the rate is available on (type "include") or not available on
+// (type "exclude").
+type UserDeviceTypes struct {
+	Type           string       `xml:"type,attr"`  // [include|exclude]
+	UserDeviceType []DeviceType `xml:",omitempty"` // [desktop|mobile|tablet]
+}
+
+func (u *UserDeviceTypes) devices() []string {
+	devices := make([]string, len(u.UserDeviceType))
+	for i, d := range u.UserDeviceType {
+		devices[i] = string(d)
+	}
+	return devices
+}
+
+// Qualifications a user needs for the rate.
+type RateQualifiesFor struct {
+	Qualification []string `xml:",omitempty"`
+}
+
+// Returns true if a user with the given context meets all conditions of the
+// rule.
+func (d *RateRuleDefinition) Eligible(ctx UserContext) bool {
+	if d.Private && !ctx.SignedIn {
+		return false
+	}
+	if d.Member && !ctx.Member {
+		return false
+	}
+	if c := d.UserCountryCodes; c != nil && !includes(c.Type, c.UserCountryCode, ctx.Country) {
+		return false
+	}
+	if c := d.UserDeviceTypes; c != nil && !includes(c.Type, c.devices(), string(ctx.Device)) {
+		return false
+	}
+	if q := d.RateQualifiesFor; q != nil {
+		for _, required := range q.Qualification {
+			if !contains(ctx.Qualifications, required) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Returns true if the value is allowed by an include or exclude list.
+func includes(listType string, list []string, value string) bool {
+	if listType == "exclude" {
+		return !contains(list, value)
+	}
+	return contains(list, value)
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the definition with the given ID or nil if there is none.
+func (d *RateRuleDefinitions) Get(id string) *RateRuleDefinition {
+	for i := range d.RateRuleDefinition {
+		if d.RateRuleDefinition[i].ID == id {
+			return &d.RateRuleDefinition[i]
+		}
+	}
+	return nil
+}
+
+// Checks that every rate_rule_id used in the given message is defined. The
+// returned error is of type ValidationErrors and lists every rate that
+// refers to an undefined rule, or nil if all rules are defined.
+func (d *RateRuleDefinitions) Check(t *Transaction) error {
+	ids := map[string]bool{}
+	for _, def := range d.RateRuleDefinition {
+		ids[def.ID] = true
+	}
+
+	v := validator{}
+	check := func(rate *Rate, path string) {
+		if rate.RateRuleID != "" && !ids[rate.RateRuleID] {
+			v.add(path+"/@rate_rule_id", "undefined rate rule %q", rate.RateRuleID)
+		}
+	}
+	checkRates := func(rates *Rates, path string) {
+		if rates == nil {
+			return
+		}
+		for i := range rates.Rate {
+			check(&rates.Rate[i], index(path+"/Rate", i))
+		}
+	}
+
+	for i := range t.Result {
+		r, path := &t.Result[i], index("Transaction/Result", i)
+		check(&r.Rate, path)
+		checkRates(r.Rates, path+"/Rates")
+		for j := range r.RoomBundle {
+			b, bp := &r.RoomBundle[j], index(path+"/RoomBundle", j)
+			check(&b.Rate, bp)
+			checkRates(b.Rates, bp+"/Rates")
+		}
+	}
+	return v.err()
+}
+
+// Reads a Rate Rules file.
+func DecodeRateRules(r io.Reader) (*RateRuleDefinitions, error) {
+	var d RateRuleDefinitions
+	if err := decodeFile(r, &d); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// Writes the given definitions as Rate Rules file, including the XML
+// declaration.
+func EncodeRateRules(w io.Writer, d *RateRuleDefinitions) error {
+	return encodeFile(w, d)
+}
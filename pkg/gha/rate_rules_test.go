@@ -0,0 +1,127 @@
+package gha
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func readRateRules(t *testing.T) *RateRuleDefinitions {
+	var d *RateRuleDefinitions
+	readTestFile(t, "RateRules-Example.xml", func(r io.Reader) (err error) {
+		d, err = DecodeRateRules(r)
+		return err
+	})
+	return d
+}
+
+func TestRateRulesFile(t *testing.T) {
+	got := readRateRules(t)
+
+	want := []RateRuleDefinition{
+		{
+			ID:          "mobile",
+			Description: "Mobile users only",
+			UserDeviceTypes: &UserDeviceTypes{
+				Type:           "include",
+				UserDeviceType: []DeviceType{DeviceMobile},
+			},
+		},
+		{
+			ID:      "member-not-us",
+			Private: true,
+			Member:  true,
+			UserCountryCodes: &UserCountryCodes{
+				Type:            "exclude",
+				UserCountryCode: []string{"US"},
+			},
+		},
+		{
+			ID: "senior-gov",
+			RateQualifiesFor: &RateQualifiesFor{
+				Qualification: []string{"senior", "government"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got.RateRuleDefinition, want) {
+		printError(t, got.RateRuleDefinition, want)
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeRateRules(&buf, got); err != nil {
+		t.Fatalf("Encoding file failed with error: %v", err)
+	}
+	again, err := DecodeRateRules(&buf)
+	if err != nil {
+		t.Fatalf("Parsing encoded file failed with error: %v", err)
+	}
+	if !reflect.DeepEqual(again, got) {
+		printError(t, again, got)
+	}
+}
+
+func TestRateRuleEligible(t *testing.T) {
+	rules := readRateRules(t)
+
+	tests := []struct {
+		rule string
+		ctx  UserContext
+		want bool
+	}{
+		{"mobile", UserContext{Device: "mobile"}, true},
+		{"mobile", UserContext{Device: "desktop"}, false},
+		{"member-not-us", UserContext{Country: "DE", SignedIn: true, Member: true}, true},
+		{"member-not-us", UserContext{Country: "US", SignedIn: true, Member: true}, false},
+		{"member-not-us", UserContext{Country: "DE", Member: true}, false},
+		{"member-not-us", UserContext{Country: "DE", SignedIn: true}, false},
+		{"senior-gov", UserContext{Qualifications: []string{"government", "senior"}}, true},
+		{"senior-gov", UserContext{Qualifications: []string{"senior"}}, false},
+	}
+
+	for _, tt := range tests {
+		if got := rules.Get(tt.rule).Eligible(tt.ctx); got != tt.want {
+			t.Errorf("Eligible(%v, %+v) got %v, want: %v", tt.rule, tt.ctx, got, tt.want)
+		}
+	}
+	if rules.Get("unknown") != nil {
+		t.Errorf("Get(unknown) got a definition, want: nil")
+	}
+}
+
+func TestRateRulesCheck(t *testing.T) {
+	rules := readRateRules(t)
+
+	request, err := ioutil.ReadFile("./testdata/Transaction-BaseRateAndConditionalRate.xml")
+	if err != nil {
+		t.Fatalf("File reading error %v", err)
+	}
+	var tx Transaction
+	if err = xml.Unmarshal(request, &tx); err != nil {
+		t.Fatalf("Parsing request data failed with error: %v", err)
+	}
+	if err := rules.Check(&tx); err != nil {
+		t.Errorf("Check failed with error: %v", err)
+	}
+
+	request, err = ioutil.ReadFile("./testdata/Transaction-RoomBundle.xml")
+	if err != nil {
+		t.Fatalf("File reading error %v", err)
+	}
+	tx = Transaction{}
+	if err = xml.Unmarshal(request, &tx); err != nil {
+		t.Fatalf("Parsing request data failed with error: %v", err)
+	}
+	tx.Result[0].RoomBundle[1].Rates = &Rates{Rate: []Rate{{RateRuleID: "loyalty"}}}
+	tx.Result[0].RateRuleID = "web"
+
+	want := ValidationErrors{
+		{"Transaction/Result[1]/@rate_rule_id", `undefined rate rule "web"`},
+		{"Transaction/Result[1]/RoomBundle[2]/Rates/Rate[1]/@rate_rule_id", `undefined rate rule "loyalty"`},
+	}
+	if err := rules.Check(&tx); !reflect.DeepEqual(err, want) {
+		printError(t, err, want)
+	}
+}
@@ -0,0 +1,283 @@
+package gha
+
+import (
+	"sync"
+	"time"
+)
+
+// Stay restrictions of a date. Zero values stand for no restriction.
+//
+// * Closed:
+//   No stay may include the night of the date (stop sell).
+// * MinNightStay / MaxNightStay:
+//   Min. and max. number of nights of a stay that starts on the date.
+// * MinAdvancePurchase / MaxAdvancePurchase:
+//   Min. and max. number of days between booking and a check-in on the date.
+// * ClosedToArrival / ClosedToDeparture:
+//   No check-in or no check-out on the date.
+type Restriction struct {
+	Closed             bool
+	MinNightStay       int
+	MaxNightStay       int
+	MinAdvancePurchase int
+	MaxAdvancePurchase int
+	ClosedToArrival    bool
+	ClosedToDeparture  bool
+}
+
+// Returns the most restrictive combination of both restrictions.
+func (r Restriction) merge(o Restriction) Restriction {
+	return Restriction{
+		Closed:             r.Closed || o.Closed,
+		MinNightStay:       maxLimit(r.MinNightStay, o.MinNightStay),
+		MaxNightStay:       minLimit(r.MaxNightStay, o.MaxNightStay),
+		MinAdvancePurchase: maxLimit(r.MinAdvancePurchase, o.MinAdvancePurchase),
+		MaxAdvancePurchase: minLimit(r.MaxAdvancePurchase, o.MaxAdvancePurchase),
+		ClosedToArrival:    r.ClosedToArrival || o.ClosedToArrival,
+		ClosedToDeparture:  r.ClosedToDeparture || o.ClosedToDeparture,
+	}
+}
+
+// Returns the reasons of both, with the most restrictive thresholds.
+func (u Unavailable) merge(o Unavailable) Unavailable {
+	return Unavailable{
+		NoVacancy:          u.NoVacancy || o.NoVacancy,
+		MinNightStay:       mergeThreshold(u.MinNightStay, o.MinNightStay, maxLimit),
+		MaxNightStay:       mergeThreshold(u.MaxNightStay, o.MaxNightStay, minLimit),
+		MinAdvancePurchase: mergeThreshold(u.MinAdvancePurchase, o.MinAdvancePurchase, maxLimit),
+		MaxAdvancePurchase: mergeThreshold(u.MaxAdvancePurchase, o.MaxAdvancePurchase, minLimit),
+		ClosedToArrival:    u.ClosedToArrival || o.ClosedToArrival,
+		ClosedToDeparture:  u.ClosedToDeparture || o.ClosedToDeparture,
+		PriceIssue:         u.PriceIssue || o.PriceIssue,
+	}
+}
+
+func mergeThreshold(a, b *Threshold, limit func(a, b int) int) *Threshold {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	return &Threshold{limit(a.Value, b.Value)}
+}
+
+func maxLimit(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// Returns the smaller limit, where 0 means no limit.
+func minLimit(a, b int) int {
+	if a == 0 || (b != 0 && b < a) {
+		return b
+	}
+	return a
+}
+
+// Identifies the rooms and rates of a property that restrictions apply to.
+// An empty RoomID or RateRuleID matches all rooms or rates of the property.
+type RestrictionKey struct {
+	Property   string
+	RoomID     string
+	RateRuleID string
+}
+
+// Returns the given key and the keys of the rooms and rates it belongs to.
+func (k RestrictionKey) levels() []RestrictionKey {
+	keys := []RestrictionKey{{Property: k.Property}}
+	if k.RoomID != "" {
+		keys = append(keys, RestrictionKey{Property: k.Property, RoomID: k.RoomID})
+	}
+	if k.RateRuleID != "" {
+		keys = append(keys, RestrictionKey{Property: k.Property, RateRuleID: k.RateRuleID})
+	}
+	if k.RoomID != "" && k.RateRuleID != "" {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Stay restrictions by property, room, rate and date. Restrictions of a
+// property apply to all its rooms and rates, restrictions of a room to all
+// its rates; an itinerary must satisfy all restrictions that apply to it.
+//
+// A nil *Restrictions has no restrictions. Restrictions are safe for
+// concurrent use, e.g. Set while a QueryHandler applies them; Zones must not
+// be changed once they are in use.
+type Restrictions struct {
+	// Time zones of the properties, which determine the booking date of the
+	// advance purchase restrictions.
+	Zones PropertyZones
+
+	mu    sync.RWMutex
+	dates map[RestrictionKey]map[time.Time]Restriction
+}
+
+// Returns new, empty restrictions for properties in the given time zones,
+// which can be nil.
+func NewRestrictions(zones PropertyZones) *Restrictions {
+	return &Restrictions{Zones: zones}
+}
+
+// Sets the restriction of all dates between first and last, inclusive. It
+// replaces any restriction set before for the same key and dates.
+func (r *Restrictions) Set(key RestrictionKey, first, last Date, restriction Restriction) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.dates == nil {
+		r.dates = map[RestrictionKey]map[time.Time]Restriction{}
+	}
+	if r.dates[key] == nil {
+		r.dates[key] = map[time.Time]Restriction{}
+	}
+	for d, end := truncateDate(first), truncateDate(last); !d.After(end); d = d.AddDate(0, 0, 1) {
+		r.dates[key][d] = restriction
+	}
+}
+
+// Returns the combined restrictions of the given date that apply to the key.
+func (r *Restrictions) Get(key RestrictionKey, date Date) Restriction {
+	return r.get(key, truncateDate(date))
+}
+
+func (r *Restrictions) get(key RestrictionKey, date time.Time) Restriction {
+	var res Restriction
+	if r == nil {
+		return res
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, k := range key.levels() {
+		res = res.merge(r.dates[k][date])
+	}
+	return res
+}
+
+// Returns the reasons why the itinerary with the given check-in date and
+// number of nights cannot be booked at the given time, or nil if it can:
+// - The length of stay and the advance purchase restrictions of the check-in
+//   date, and its closed to arrival restriction.
+// - The closed to departure restriction of the check-out date.
+// - The closed restriction of every night of the stay.
+func (r *Restrictions) Evaluate(key RestrictionKey, checkin Date, nights int, now time.Time) *Unavailable {
+	if r == nil {
+		return nil
+	}
+	first := truncateDate(checkin)
+	arrival := r.get(key, first)
+
+	var u Unavailable
+	for i := 0; i < nights; i++ {
+		if r.get(key, first.AddDate(0, 0, i)).Closed {
+			u.NoVacancy = true
+			break
+		}
+	}
+	if arrival.MinNightStay > 0 && nights < arrival.MinNightStay {
+		u.MinNightStay = &Threshold{arrival.MinNightStay}
+	}
+	if arrival.MaxNightStay > 0 && nights > arrival.MaxNightStay {
+		u.MaxNightStay = &Threshold{arrival.MaxNightStay}
+	}
+
+	local := now.In(r.Zones.Location(Property{key.Property}))
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+	advance := int(first.Sub(today) / day)
+	if arrival.MinAdvancePurchase > 0 && advance < arrival.MinAdvancePurchase {
+		u.MinAdvancePurchase = &Threshold{arrival.MinAdvancePurchase}
+	}
+	if arrival.MaxAdvancePurchase > 0 && advance > arrival.MaxAdvancePurchase {
+		u.MaxAdvancePurchase = &Threshold{arrival.MaxAdvancePurchase}
+	}
+
+	u.ClosedToArrival = Flag(arrival.ClosedToArrival)
+	u.ClosedToDeparture = Flag(r.get(key, first.AddDate(0, 0, nights)).ClosedToDeparture)
+
+	if !u.hasReason() {
+		return nil
+	}
+	return &u
+}
+
+// Returns the given results with the itineraries that cannot be booked at
+// the given time removed:
+// - A result whose own room and rate are restricted is replaced by an
+//   unavailable result, see NewUnavailableResult.
+// - Restricted <Rate> elements in <Rates> and restricted <RoomBundle>
+//   elements of other results are dropped, as well as room bundles without
+//   <Baserate> whose rates are all restricted.
+// - A result without <Baserate> that has no rates and room bundles left is
+//   replaced by an unavailable result with the reasons of all of them.
+//
+// Results without nights and unavailable results are kept as they are.
+func (r *Restrictions) Apply(results []Result, now time.Time) []Result {
+	if r == nil {
+		return results
+	}
+	out := make([]Result, 0, len(results))
+	for _, res := range results {
+		if res.Nights == 0 || res.Unavailable != nil {
+			out = append(out, res)
+			continue
+		}
+		nights := int(res.Nights)
+		key := RestrictionKey{res.Property.ID, res.RoomID, res.RateRuleID}
+		if u := r.Evaluate(key, res.Checkin, nights, now); u != nil {
+			out = append(out, NewUnavailableResult(res.Property, res.Checkin, res.Nights, *u))
+			continue
+		}
+
+		var reasons Unavailable
+		restricted := func(k RestrictionKey) bool {
+			u := r.Evaluate(k, res.Checkin, nights, now)
+			if u != nil {
+				reasons = reasons.merge(*u)
+			}
+			return u != nil
+		}
+		res.Rates = filterRates(res.Rates, key, restricted)
+		var bundles []RoomBundle
+		for _, b := range res.RoomBundle {
+			k := RestrictionKey{res.Property.ID, b.RoomID, b.RateRuleID}
+			if restricted(k) {
+				continue
+			}
+			if b.Rates != nil {
+				if b.Rates = filterRates(b.Rates, k, restricted); b.Rates == nil && b.Baserate == nil {
+					continue
+				}
+			}
+			bundles = append(bundles, b)
+		}
+		res.RoomBundle = bundles
+
+		if reasons.hasReason() && res.Baserate == nil && res.Rates == nil && len(res.RoomBundle) == 0 {
+			out = append(out, NewUnavailableResult(res.Property, res.Checkin, res.Nights, reasons))
+			continue
+		}
+		out = append(out, res)
+	}
+	return out
+}
+
+// Returns the rates that are not restricted, or nil if there are none.
+func filterRates(rates *Rates, key RestrictionKey, restricted func(RestrictionKey) bool) *Rates {
+	if rates == nil {
+		return nil
+	}
+	var kept []Rate
+	for _, rate := range rates.Rate {
+		key.RateRuleID = rate.RateRuleID
+		if !restricted(key) {
+			kept = append(kept, rate)
+		}
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+	return &Rates{Rate: kept}
+}
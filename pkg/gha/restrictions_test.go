@@ -0,0 +1,144 @@
+package gha
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func newTestRestrictions() *Restrictions {
+	zones, _ := LoadPropertyZones(map[string]string{"1234": "Pacific/Auckland"})
+	r := NewRestrictions(zones)
+	r.Set(RestrictionKey{Property: "1234"}, newDate("2018-06-01"), newDate("2018-06-30"),
+		Restriction{MinNightStay: 2, MaxAdvancePurchase: 60})
+	r.Set(RestrictionKey{Property: "1234", RoomID: "A"}, newDate("2018-06-10"), newDate("2018-06-10"),
+		Restriction{MinNightStay: 3, ClosedToArrival: true})
+	r.Set(RestrictionKey{Property: "1234", RateRuleID: "early"}, newDate("2018-06-01"), newDate("2018-06-30"),
+		Restriction{MinAdvancePurchase: 14})
+	r.Set(RestrictionKey{Property: "1234", RoomID: "A"}, newDate("2018-06-15"), newDate("2018-06-15"),
+		Restriction{Closed: true})
+	r.Set(RestrictionKey{Property: "1234"}, newDate("2018-06-20"), newDate("2018-06-20"),
+		Restriction{ClosedToDeparture: true, MaxNightStay: 7})
+	return r
+}
+
+func TestRestrictionsGet(t *testing.T) {
+	r := newTestRestrictions()
+
+	got := r.Get(RestrictionKey{"1234", "A", "early"}, newDate("2018-06-10"))
+	want := Restriction{MinNightStay: 3, MinAdvancePurchase: 14, MaxAdvancePurchase: 60, ClosedToArrival: true}
+	if got != want {
+		printError(t, got, want)
+	}
+
+	got = r.Get(RestrictionKey{"1234", "B", ""}, newDate("2018-06-10"))
+	want = Restriction{MinNightStay: 2, MaxAdvancePurchase: 60}
+	if got != want {
+		printError(t, got, want)
+	}
+
+	if got := r.Get(RestrictionKey{"5678", "A", ""}, newDate("2018-06-10")); got != (Restriction{}) {
+		t.Errorf("Get got %+v, want: no restriction", got)
+	}
+	var none *Restrictions
+	if got := none.Get(RestrictionKey{"1234", "", ""}, newDate("2018-06-10")); got != (Restriction{}) {
+		t.Errorf("Get of nil restrictions got %+v, want: no restriction", got)
+	}
+}
+
+func TestRestrictionsEvaluate(t *testing.T) {
+	r := newTestRestrictions()
+	// 2018-06-01 in Auckland, i.e. the check-in on 2018-06-10 is 9 days ahead.
+	now := time.Date(2018, 5, 31, 13, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		key     RestrictionKey
+		checkin string
+		nights  int
+		want    *Unavailable
+	}{
+		{"Sellable", RestrictionKey{"1234", "B", ""}, "2018-06-10", 2, nil},
+		{"Unrestricted property", RestrictionKey{"5678", "A", ""}, "2018-06-10", 1, nil},
+		{"Min. nights of property", RestrictionKey{"1234", "B", ""}, "2018-06-11", 1,
+			&Unavailable{MinNightStay: &Threshold{2}}},
+		{"Room restrictions", RestrictionKey{"1234", "A", ""}, "2018-06-10", 2,
+			&Unavailable{MinNightStay: &Threshold{3}, ClosedToArrival: true}},
+		{"Min. advance purchase of rate", RestrictionKey{"1234", "B", "early"}, "2018-06-12", 2,
+			&Unavailable{MinAdvancePurchase: &Threshold{14}}},
+		{"Within max. advance purchase", RestrictionKey{"1234", "", ""}, "2018-06-30", 2, nil},
+		{"Closed night", RestrictionKey{"1234", "A", ""}, "2018-06-13", 3,
+			&Unavailable{NoVacancy: true}},
+		{"Closed night is check-out", RestrictionKey{"1234", "A", ""}, "2018-06-13", 2, nil},
+		{"Closed to departure", RestrictionKey{"1234", "B", ""}, "2018-06-17", 3,
+			&Unavailable{ClosedToDeparture: true}},
+		{"Max. nights", RestrictionKey{"1234", "B", ""}, "2018-06-20", 8,
+			&Unavailable{MaxNightStay: &Threshold{7}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := r.Evaluate(tt.key, newDate(tt.checkin), tt.nights, now)
+			if !reflect.DeepEqual(got, tt.want) {
+				printError(t, got, tt.want)
+			}
+		})
+	}
+
+	u := r.Evaluate(RestrictionKey{"1234", "", ""}, newDate("2018-06-10"), 2, now.AddDate(0, -2, 0))
+	if want := (&Unavailable{MaxAdvancePurchase: &Threshold{60}}); !reflect.DeepEqual(u, want) {
+		printError(t, u, want)
+	}
+}
+
+func TestRestrictionsApply(t *testing.T) {
+	r := newTestRestrictions()
+	now := time.Date(2018, 5, 31, 13, 0, 0, 0, time.UTC)
+	checkin := newDate("2018-06-10")
+
+	results := []Result{
+		{Property: Property{"1234"}, Checkin: checkin, Nights: 2, RoomID: "A", Rate: Rate{Baserate: &Money{}}},
+		{
+			Property: Property{"1234"}, Checkin: checkin, Nights: 2, RoomID: "B",
+			Rates: &Rates{Rate: []Rate{{RateRuleID: "early"}, {RateRuleID: "flex"}}},
+			RoomBundle: []RoomBundle{
+				{RoomID: "A"},
+				{RoomID: "C", Rate: Rate{Baserate: &Money{}}, Rates: &Rates{Rate: []Rate{{RateRuleID: "early"}}}},
+			},
+		},
+		{
+			Property: Property{"1234"}, Checkin: checkin, Nights: 2, RoomID: "B",
+			Rates: &Rates{Rate: []Rate{{RateRuleID: "early"}}},
+			RoomBundle: []RoomBundle{
+				{RoomID: "A", Rate: Rate{Baserate: &Money{}}},
+				{RoomID: "C", Rates: &Rates{Rate: []Rate{{RateRuleID: "early"}}}},
+			},
+		},
+		{Property: Property{"1234"}, Checkin: checkin},
+	}
+
+	got := r.Apply(results, now)
+	want := []Result{
+		NewUnavailableResult(Property{"1234"}, checkin, 2,
+			Unavailable{MinNightStay: &Threshold{3}, ClosedToArrival: true}),
+		{
+			Property: Property{"1234"}, Checkin: checkin, Nights: 2, RoomID: "B",
+			Rates:      &Rates{Rate: []Rate{{RateRuleID: "flex"}}},
+			RoomBundle: []RoomBundle{{RoomID: "C", Rate: Rate{Baserate: &Money{}}}},
+		},
+		NewUnavailableResult(Property{"1234"}, checkin, 2, Unavailable{
+			MinNightStay:       &Threshold{3},
+			MinAdvancePurchase: &Threshold{14},
+			ClosedToArrival:    true,
+		}),
+		{Property: Property{"1234"}, Checkin: checkin},
+	}
+	if !reflect.DeepEqual(got, want) {
+		printError(t, got, want)
+	}
+
+	var none *Restrictions
+	if got := none.Apply(results, now); !reflect.DeepEqual(got, results) {
+		printError(t, got, results)
+	}
+}
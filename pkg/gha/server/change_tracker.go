@@ -0,0 +1,112 @@
+package server
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// A price change of a property.
+//
+// A change either affects a single itinerary (Nights > 0) or every stay that
+// includes a night between Checkin and LastDate (Nights == 0), for example
+// when the nightly rate of these dates has been updated.
+type Change struct {
+	Property string
+	Checkin  time.Time
+	Nights   int
+	LastDate time.Time
+
+	// Time the change has been recorded.
+	Time time.Time
+}
+
+// Records the price changes of properties, so that a Hint Response can be
+// built from all changes since Google last fetched hints.
+//
+// A ChangeTracker is safe for concurrent use.
+type ChangeTracker struct {
+	// Returns the time a change is recorded at. Defaults to time.Now.
+	Now func() time.Time
+
+	mu      sync.Mutex
+	changes []Change
+}
+
+// Returns a new, empty ChangeTracker.
+func NewChangeTracker() *ChangeTracker {
+	return &ChangeTracker{}
+}
+
+// Records a price change of a single itinerary. It returns an error and
+// records nothing if nights is less than 1.
+func (t *ChangeTracker) RecordItinerary(property string, checkin time.Time, nights int) error {
+	if nights < 1 {
+		return fmt.Errorf("server: itinerary must have at least 1 night, got %d", nights)
+	}
+	t.record(Change{
+		Property: property,
+		Checkin:  truncateDate(checkin),
+		Nights:   nights,
+	})
+	return nil
+}
+
+// Records a price change of all stays that include a night between first and
+// last, inclusive.
+func (t *ChangeTracker) RecordStayDates(property string, first, last time.Time) {
+	first, last = truncateDate(first), truncateDate(last)
+	if last.Before(first) {
+		first, last = last, first
+	}
+	t.record(Change{
+		Property: property,
+		Checkin:  first,
+		LastDate: last,
+	})
+}
+
+func (t *ChangeTracker) record(c Change) {
+	now := time.Now
+	if t.Now != nil {
+		now = t.Now
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	c.Time = now()
+	t.changes = append(t.changes, c)
+}
+
+// Returns all changes recorded at or after the given time, oldest first.
+func (t *ChangeTracker) Since(since time.Time) []Change {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// changes are appended in chronological order
+	i := sort.Search(len(t.changes), func(i int) bool {
+		return !t.changes[i].Time.Before(since)
+	})
+	return append([]Change(nil), t.changes[i:]...)
+}
+
+// Removes all changes recorded before the given time. Call it periodically
+// with a time well before the last fetch time of Google to limit the memory
+// used by the tracker.
+func (t *ChangeTracker) Prune(before time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	i := sort.Search(len(t.changes), func(i int) bool {
+		return !t.changes[i].Time.Before(before)
+	})
+	t.changes = append([]Change(nil), t.changes[i:]...)
+}
+
+// Returns the date of the given time at midnight UTC.
+func truncateDate(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"testing"
+	"time"
+)
+
+func date(value string) time.Time {
+	d, _ := time.Parse("2006-01-02", value)
+	return d
+}
+
+// Returns a clock that advances by a minute on every call.
+func newTestClock(start time.Time) func() time.Time {
+	now := start
+	return func() time.Time {
+		now = now.Add(time.Minute)
+		return now
+	}
+}
+
+func TestChangeTracker(t *testing.T) {
+	start := time.Date(2019, 6, 3, 22, 0, 0, 0, time.UTC)
+
+	tracker := NewChangeTracker()
+	tracker.Now = newTestClock(start)
+	tracker.RecordItinerary("12345", date("2018-07-03"), 3)                   // 22:01
+	tracker.RecordItinerary("12345", date("2018-07-03").Add(15*time.Hour), 4) // 22:02
+	tracker.RecordStayDates("67890", date("2018-07-06"), date("2018-07-03"))  // 22:03
+
+	changes := tracker.Since(start.Add(2 * time.Minute))
+	if len(changes) != 2 {
+		t.Fatalf("len(Since) got %v, want: 2", len(changes))
+	}
+	if !changes[0].Checkin.Equal(date("2018-07-03")) {
+		t.Errorf("Checkin got %v, want: 2018-07-03", changes[0].Checkin)
+	}
+	if changes[1].Nights != 0 || !changes[1].Checkin.Equal(date("2018-07-03")) || !changes[1].LastDate.Equal(date("2018-07-06")) {
+		t.Errorf("Change got %+v, want stay dates 2018-07-03 to 2018-07-06", changes[1])
+	}
+
+	tracker.Prune(start.Add(3 * time.Minute))
+	if got := len(tracker.Since(time.Time{})); got != 1 {
+		t.Errorf("len(Since) after Prune got %v, want: 1", got)
+	}
+}
+
+func TestChangeTrackerRejectsEmptyStays(t *testing.T) {
+	tracker := NewChangeTracker()
+	for _, nights := range []int{0, -1} {
+		if err := tracker.RecordItinerary("12345", date("2018-07-03"), nights); err == nil {
+			t.Errorf("RecordItinerary with %d nights got no error", nights)
+		}
+	}
+	if got := len(tracker.Since(time.Time{})); got != 0 {
+		t.Errorf("len(Since) got %v, want: 0", got)
+	}
+}
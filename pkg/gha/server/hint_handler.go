@@ -0,0 +1,57 @@
+package server
+
+import (
+	"encoding/xml"
+	"net/http"
+	"time"
+
+	"github.com/f-go/link/pkg/gha"
+)
+
+// HTTP handler that answers a Hint Request message with a Hint Response that
+// contains every itinerary changed since the <LastFetchTime> of the request.
+//
+// The changes are grouped into <Item> elements by a gha.HintBuilder, see
+// there for the shape of the items. Properties share an <Item> as far as the
+// query control options allow.
+type HintHandler struct {
+	Tracker *ChangeTracker
+
+	// Query control options of the partner account, optional.
+	Control *gha.QueryControl
+}
+
+// Returns a new HintHandler that answers with the changes of the given tracker.
+func NewHintHandler(tracker *ChangeTracker) *HintHandler {
+	return &HintHandler{Tracker: tracker}
+}
+
+func (h *HintHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req gha.HintRequest
+	if err := xml.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize)).Decode(&req); err != nil {
+		http.Error(w, "invalid hint request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	changes := h.Tracker.Since(time.Time(req.LastFetchTime))
+	writeXML(w, buildHint(changes, h.Control))
+}
+
+// Returns the Hint Response for the given changes.
+func buildHint(changes []Change, control *gha.QueryControl) gha.Hint {
+	b := gha.NewHintBuilder(control)
+	for _, c := range changes {
+		if c.Nights > 0 {
+			b.AddItinerary(gha.Property{ID: c.Property}, gha.Date(c.Checkin), c.Nights)
+		} else {
+			b.AddStayDates(gha.Property{ID: c.Property}, gha.Date(c.Checkin), gha.Date(c.LastDate))
+		}
+	}
+	return b.Hint()
+}
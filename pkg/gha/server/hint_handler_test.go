@@ -0,0 +1,78 @@
+package server
+
+import (
+	"encoding/xml"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/f-go/link/pkg/gha"
+)
+
+func ghaDate(value string) gha.Date {
+	d, _ := gha.NewDate(value)
+	return d
+}
+
+func TestHintHandler(t *testing.T) {
+	tracker := NewChangeTracker()
+	tracker.Now = newTestClock(time.Date(2019, 6, 3, 22, 53, 0, 0, time.UTC))
+	tracker.RecordItinerary("99999", date("2018-07-03"), 1) // 22:54, before LastFetchTime
+	tracker.RecordItinerary("12345", date("2018-07-03"), 3) // 22:55, ...
+	tracker.RecordItinerary("12345", date("2018-07-03"), 3) // duplicate
+	tracker.RecordItinerary("67890", date("2018-07-03"), 3) // same itinerary as 12345
+	tracker.RecordItinerary("12345", date("2018-07-10"), 2) // not adjacent
+	tracker.RecordItinerary("67890", date("2018-07-04"), 1) // adjacent to 2018-07-03
+	tracker.RecordItinerary("67890", date("2018-07-05"), 7) // adjacent to 2018-07-04
+	tracker.RecordStayDates("12345", date("2018-08-01"), date("2018-08-03"))
+	tracker.RecordStayDates("12345", date("2018-08-04"), date("2018-08-04")) // adjacent
+	tracker.RecordStayDates("12345", date("2018-08-10"), date("2018-08-10"))
+
+	rec := serve(t, NewHintHandler(tracker), http.MethodPost, "../testdata/HintRequest.xml")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status got %v, want: %v (%s)", rec.Code, http.StatusOK, rec.Body)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != contentType {
+		t.Errorf("Content-Type got %v, want: %v", ct, contentType)
+	}
+
+	var got gha.Hint
+	if err := xml.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("Parsing response failed with error: %v", err)
+	}
+
+	want := gha.Hint{
+		Item: []gha.Item{
+			gha.NewExactItem(ghaDate("2018-07-03"), 3, gha.Property{ID: "12345"}),
+			gha.NewExactItem(ghaDate("2018-07-10"), 2, gha.Property{ID: "12345"}),
+			gha.NewCheckinRangeItem(ghaDate("2018-07-03"), ghaDate("2018-07-05"), gha.Property{ID: "67890"}),
+			gha.NewRangedStayItem(ghaDate("2018-08-01"), ghaDate("2018-08-04"), gha.Property{ID: "12345"}),
+			gha.NewRangedStayItem(ghaDate("2018-08-10"), ghaDate("2018-08-10"), gha.Property{ID: "12345"}),
+		},
+	}
+
+	if len(got.Item) != len(want.Item) {
+		t.Fatalf("len(Item) got %v, want: %v\n%s", len(got.Item), len(want.Item), rec.Body)
+	}
+	for i := range want.Item {
+		if !reflect.DeepEqual(got.Item[i].Property, want.Item[i].Property) ||
+			!reflect.DeepEqual(got.Item[i].Stay, want.Item[i].Stay) ||
+			!reflect.DeepEqual(got.Item[i].StaysIncludingRange, want.Item[i].StaysIncludingRange) ||
+			got.Item[i].FirstDate != want.Item[i].FirstDate ||
+			got.Item[i].LastDate != want.Item[i].LastDate {
+			t.Errorf("Item[%d]\ngot:  %+v\nwant: %+v", i, got.Item[i], want.Item[i])
+		}
+	}
+}
+
+func TestHintHandlerNoChanges(t *testing.T) {
+	rec := serve(t, NewHintHandler(NewChangeTracker()), http.MethodPost, "../testdata/HintRequest.xml")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status got %v, want: %v (%s)", rec.Code, http.StatusOK, rec.Body)
+	}
+	if !strings.HasSuffix(rec.Body.String(), "<Hint></Hint>") {
+		t.Errorf("Response got %s, want an empty <Hint>", rec.Body)
+	}
+}
@@ -0,0 +1,188 @@
+// Package server provides HTTP handlers that answer the requests Google sends
+// to a rate provider in the Pull and Pull with Hints delivery modes.
+package server
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/f-go/link/pkg/gha"
+)
+
+// Max. size of a request body accepted by the handlers.
+const maxRequestSize = 10 << 20
+
+// Content type of the XML responses.
+const contentType = "application/xml; charset=utf-8"
+
+// Provides the prices for a pricing Query.
+type PricingProvider interface {
+	// Returns the <Result> elements for the properties and itineraries of the
+	// given query. A Live Query carries a <Context> that the prices must match.
+	Prices(ctx context.Context, q *gha.Query) ([]gha.Result, error)
+}
+
+// Provides the room and Room Bundle metadata for a metadata Query.
+type MetadataProvider interface {
+	// Returns one <PropertyDataSet> for each of the given properties that
+	// room or package data is available for.
+	PropertyData(ctx context.Context, properties []gha.Property) ([]gha.PropertyDataSet, error)
+}
+
+// HTTP handler that answers pricing and metadata Query messages with a
+// Transaction message.
+//
+// Pricing queries are passed to the PricingProvider, metadata queries to the
+// MetadataProvider. If no provider is set for a query type, the handler
+// responds with 501 Not Implemented. Queries that exceed the limits of the
+// query control options are answered with 400 Bad Request; Live Queries that
+// the options disable are answered like any other query unless RejectDisabled
+// is set. Priced itineraries that the stay restrictions do not allow are
+// answered as unavailable, see gha.Restrictions.Apply.
+type QueryHandler struct {
+	Pricing  PricingProvider
+	Metadata MetadataProvider
+
+	// Query control options of the partner account, optional.
+	Control *gha.QueryControl
+
+	// Answers Live and latency sensitive queries that Control disables with
+	// 400 Bad Request instead, see gha.QueryControl.Enables.
+	RejectDisabled bool
+
+	// Stay restrictions of the properties, optional.
+	Restrictions *gha.Restrictions
+
+	// Partner key that is set on every Transaction, optional.
+	Partner string
+
+	// Returns the ID of a new Transaction. Defaults to gha.NewTransactionID.
+	NewID func() string
+
+	// Returns the timestamp of a new Transaction. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Returns a new QueryHandler for the given providers. Either provider can be
+// nil if the corresponding query type is not supported.
+func NewQueryHandler(pricing PricingProvider, metadata MetadataProvider) *QueryHandler {
+	return &QueryHandler{
+		Pricing:  pricing,
+		Metadata: metadata,
+	}
+}
+
+func (h *QueryHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var q gha.Query
+	if err := xml.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize)).Decode(&q); err != nil {
+		http.Error(w, "invalid query: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	t, err := h.answer(r.Context(), &q)
+	if err != nil {
+		var he *httpError
+		if errors.As(err, &he) {
+			http.Error(w, he.msg, he.code)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeXML(w, t)
+}
+
+// Builds the Transaction that answers the given query.
+func (h *QueryHandler) answer(ctx context.Context, q *gha.Query) (*gha.Transaction, error) {
+	if err := h.Control.Accepts(q); err != nil {
+		return nil, &httpError{http.StatusBadRequest, err.Error()}
+	}
+	if h.RejectDisabled {
+		if err := h.Control.Enables(q); err != nil {
+			return nil, &httpError{http.StatusBadRequest, err.Error()}
+		}
+	}
+	now := h.now()
+	t := h.newTransaction(now)
+
+	switch {
+	case q.PropertyList != nil:
+		if h.Pricing == nil {
+			return nil, &httpError{http.StatusNotImplemented, "pricing queries are not supported"}
+		}
+		results, err := h.Pricing.Prices(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+		t.Result = h.Restrictions.Apply(results, now)
+
+	case q.HotelInfoProperties != nil:
+		if h.Metadata == nil {
+			return nil, &httpError{http.StatusNotImplemented, "metadata queries are not supported"}
+		}
+		data, err := h.Metadata.PropertyData(ctx, q.HotelInfoProperties.Property)
+		if err != nil {
+			return nil, err
+		}
+		t.PropertyDataSet = data
+
+	default:
+		return nil, &httpError{http.StatusBadRequest, "query has neither <PropertyList> nor <HotelInfoProperties>"}
+	}
+
+	return t, nil
+}
+
+func (h *QueryHandler) now() time.Time {
+	if h.Now == nil {
+		return time.Now()
+	}
+	return h.Now()
+}
+
+func (h *QueryHandler) newTransaction(now time.Time) *gha.Transaction {
+	newID := h.NewID
+	if newID == nil {
+		newID = gha.NewTransactionID
+	}
+
+	return &gha.Transaction{
+		ID:        newID(),
+		Timestamp: gha.DateTime(now.Truncate(time.Second)),
+		Partner:   h.Partner,
+	}
+}
+
+// Error that is answered with a specific HTTP status code.
+type httpError struct {
+	code int
+	msg  string
+}
+
+func (e *httpError) Error() string {
+	return e.msg
+}
+
+// Writes v as XML document to the response.
+func writeXML(w http.ResponseWriter, v interface{}) {
+	data, err := xml.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(xml.Header))
+	_, _ = w.Write(data)
+}
@@ -0,0 +1,248 @@
+package server
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/f-go/link/pkg/gha"
+)
+
+type pricingFunc func(ctx context.Context, q *gha.Query) ([]gha.Result, error)
+
+func (f pricingFunc) Prices(ctx context.Context, q *gha.Query) ([]gha.Result, error) {
+	return f(ctx, q)
+}
+
+type metadataFunc func(ctx context.Context, properties []gha.Property) ([]gha.PropertyDataSet, error)
+
+func (f metadataFunc) PropertyData(ctx context.Context, properties []gha.Property) ([]gha.PropertyDataSet, error) {
+	return f(ctx, properties)
+}
+
+func newTestQueryHandler() *QueryHandler {
+	pricing := pricingFunc(func(ctx context.Context, q *gha.Query) ([]gha.Result, error) {
+		var results []gha.Result
+		for _, p := range q.PropertyList.Property {
+			results = append(results, gha.Result{
+				Property: p,
+				Checkin:  q.Checkin,
+				Nights:   uint8(q.Nights),
+			})
+		}
+		return results, nil
+	})
+	metadata := metadataFunc(func(ctx context.Context, properties []gha.Property) ([]gha.PropertyDataSet, error) {
+		var data []gha.PropertyDataSet
+		for _, p := range properties {
+			data = append(data, gha.PropertyDataSet{
+				Property: p,
+				RoomData: []gha.RoomData{{RoomID: "RoomType101"}},
+			})
+		}
+		return data, nil
+	})
+
+	h := NewQueryHandler(pricing, metadata)
+	h.Partner = "partner"
+	h.NewID = func() string { return "42" }
+	h.Now = func() time.Time { return time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC) }
+	return h
+}
+
+func serve(t *testing.T, h http.Handler, method, file string) *httptest.ResponseRecorder {
+	body, err := os.Open(file)
+	if err != nil {
+		t.Fatalf("File reading error %v", err)
+	}
+	defer body.Close()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, "/", body))
+	return rec
+}
+
+func TestQueryHandlerPricingQuery(t *testing.T) {
+	rec := serve(t, newTestQueryHandler(), http.MethodPost, "../testdata/Query-PricingQuery.xml")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status got %v, want: %v (%s)", rec.Code, http.StatusOK, rec.Body)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != contentType {
+		t.Errorf("Content-Type got %v, want: %v", ct, contentType)
+	}
+	if !strings.HasPrefix(rec.Body.String(), xml.Header) {
+		t.Errorf("Response does not start with the XML header: %s", rec.Body)
+	}
+
+	var got gha.Transaction
+	if err := xml.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("Parsing response failed with error: %v", err)
+	}
+
+	timestamp, _ := gha.NewDateTime("2018-06-01T12:00:00Z")
+	checkin, _ := gha.NewDate("2018-06-10")
+	want := gha.Transaction{
+		ID:        "42",
+		Timestamp: timestamp,
+		Partner:   "partner",
+	}
+	for _, id := range []string{"pid5", "pid8", "pid13", "pid21"} {
+		want.Result = append(want.Result, gha.Result{
+			Property: gha.Property{ID: id},
+			Checkin:  checkin,
+			Nights:   3,
+		})
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Transaction\ngot:  %v\nwant: %v", got, want)
+	}
+}
+
+func TestQueryHandlerRestrictions(t *testing.T) {
+	h := newTestQueryHandler()
+	h.Restrictions = gha.NewRestrictions(nil)
+	checkin, _ := gha.NewDate("2018-06-10")
+	h.Restrictions.Set(gha.RestrictionKey{Property: "pid8"}, checkin, checkin, gha.Restriction{MinNightStay: 4})
+
+	rec := serve(t, h, http.MethodPost, "../testdata/Query-PricingQuery.xml")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status got %v, want: %v (%s)", rec.Code, http.StatusOK, rec.Body)
+	}
+
+	var got gha.Transaction
+	if err := xml.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("Parsing response failed with error: %v", err)
+	}
+	if len(got.Result) != 4 {
+		t.Fatalf("len(Result) got %v, want: 4", len(got.Result))
+	}
+
+	want := gha.NewUnavailableResult(gha.Property{ID: "pid8"}, checkin, 3,
+		gha.Unavailable{MinNightStay: &gha.Threshold{Value: 4}})
+	if !reflect.DeepEqual(got.Result[1], want) {
+		t.Errorf("Result\ngot:  %v\nwant: %v", got.Result[1], want)
+	}
+	if got.Result[0].Unavailable != nil {
+		t.Errorf("Result of pid5 got unavailable, want: available")
+	}
+}
+
+func TestQueryHandlerMetadataQuery(t *testing.T) {
+	rec := serve(t, newTestQueryHandler(), http.MethodPost, "../testdata/Query-MetadataQuery.xml")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status got %v, want: %v (%s)", rec.Code, http.StatusOK, rec.Body)
+	}
+
+	var got gha.Transaction
+	if err := xml.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("Parsing response failed with error: %v", err)
+	}
+
+	if len(got.Result) != 0 {
+		t.Errorf("len(Result) got %v, want: 0", len(got.Result))
+	}
+	if len(got.PropertyDataSet) != 4 {
+		t.Fatalf("len(PropertyDataSet) got %v, want: 4", len(got.PropertyDataSet))
+	}
+	if got.PropertyDataSet[2].Property.ID != "pid13" {
+		t.Errorf("Property got %v, want: pid13", got.PropertyDataSet[2].Property.ID)
+	}
+}
+
+func TestQueryHandlerErrors(t *testing.T) {
+	failing := pricingFunc(func(ctx context.Context, q *gha.Query) ([]gha.Result, error) {
+		return nil, errors.New("backend down")
+	})
+
+	restricted := newTestQueryHandler()
+	restricted.Control = &gha.QueryControl{MaxProperties: 1}
+	strict := newTestQueryHandler()
+	strict.Control = &gha.QueryControl{}
+	strict.RejectDisabled = true
+
+	tests := []struct {
+		name    string
+		handler *QueryHandler
+		method  string
+		body    string
+		want    int
+	}{
+		{
+			"Wrong method",
+			newTestQueryHandler(),
+			http.MethodGet,
+			"",
+			http.StatusMethodNotAllowed,
+		},
+		{
+			"Malformed query",
+			newTestQueryHandler(),
+			http.MethodPost,
+			"<Query><Nights>three</Nights></Query>",
+			http.StatusBadRequest,
+		},
+		{
+			"Query without properties",
+			newTestQueryHandler(),
+			http.MethodPost,
+			"<Query></Query>",
+			http.StatusBadRequest,
+		},
+		{
+			"Metadata query without provider",
+			NewQueryHandler(failing, nil),
+			http.MethodPost,
+			"<Query><HotelInfoProperties><Property>1</Property></HotelInfoProperties></Query>",
+			http.StatusNotImplemented,
+		},
+		{
+			"Failing provider",
+			NewQueryHandler(failing, nil),
+			http.MethodPost,
+			"<Query><PropertyList><Property>1</Property></PropertyList></Query>",
+			http.StatusInternalServerError,
+		},
+		{
+			"Live query disabled",
+			restricted,
+			http.MethodPost,
+			"<Query><LatencySensitive>true</LatencySensitive><PropertyList><Property>1</Property></PropertyList></Query>",
+			http.StatusOK,
+		},
+		{
+			"Live query rejected",
+			strict,
+			http.MethodPost,
+			"<Query><LatencySensitive>true</LatencySensitive><PropertyList><Property>1</Property></PropertyList></Query>",
+			http.StatusBadRequest,
+		},
+		{
+			"Too many properties",
+			restricted,
+			http.MethodPost,
+			"<Query><PropertyList><Property>1</Property><Property>2</Property></PropertyList></Query>",
+			http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			tt.handler.ServeHTTP(rec, httptest.NewRequest(tt.method, "/", strings.NewReader(tt.body)))
+			if rec.Code != tt.want {
+				t.Errorf("Status got %v, want: %v (%s)", rec.Code, tt.want, fmt.Sprint(rec.Body))
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package gha
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// Taxes and fees file that defines how the taxes and fees of properties are
+// computed from their net rates. Google uses it to show prices including
+// taxes and fees; TaxFeeInfo.Apply fills in <Tax> and <OtherFees> of a
+// <Result> the same way.
+//
+// https://developers.google.com/hotels/hotel-prices/xml-reference/taxes-and-fees
+type TaxFeeInfo struct {
+	XMLName   xml.Name        `xml:"TaxFeeInfo"`
+	Partner   string          `xml:"partner,attr"`
+	ID        string          `xml:"id,attr"`
+	Timestamp DateTime        `xml:"timestamp,attr"`
+	Property  []PropertyTaxes `xml:",omitempty"`
+}
+
+// Returns the taxes and fees of the given property or nil if there are none.
+func (i *TaxFeeInfo) Get(property Property) *PropertyTaxes {
+	for j := range i.Property {
+		if i.Property[j].ID == property.ID {
+			return &i.Property[j]
+		}
+	}
+	return nil
+}
+
+// Container for the taxes and fees of a single property.
+type PropertyTaxes struct {
+	ID    string `xml:""`
+	Taxes *Taxes `xml:",omitempty"`
+	Fees  *Fees  `xml:",omitempty"`
+}
+
+// Container for one or more taxes, which add up to <Tax>.
+type Taxes struct {
+	Tax []Charge `xml:",omitempty"`
+}
+
+// Container for one or more fees, which add up to <OtherFees>.
+type Fees struct {
+	Fee []Charge `xml:",omitempty"`
+}
+
+// Definition of a tax or fee.
+//
+// * Type:
+//   - percent: Amount is a percentage of the base rate.
+//   - fixed:   Amount is a fixed amount in the given currency, or in the
+//              currency of the base rate if no currency is set.
+// * Basis (fixed amounts only):
+//   - per_room:   The amount is charged once for the room (default).
+//   - per_person: The amount is charged for each guest.
+// * Period (fixed amounts only):
+//   - per_stay:  The amount is charged once for the stay (default).
+//   - per_night: The amount is charged for each night.
+// * DateRange:
+//   The charge applies only to the nights in one of the ranges; a per_stay
+//   charge applies if the check-in date is in one of the ranges. If no range
+//   is set, the charge applies to all stays.
+type Charge struct {
+	Type      string      `xml:""`           // [percent|fixed]
+	Basis     string      `xml:",omitempty"` // [per_room|per_person]
+	Period    string      `xml:",omitempty"` // [per_stay|per_night]
+	Amount    Decimal     `xml:""`
+	Currency  string      `xml:",omitempty"` // ISO 4217, e.g. "USD"
+	DateRange []DateRange `xml:",omitempty"`
+}
+
+// Returns the charge for a stay with the given base rate, check-in date,
+// number of nights and guests, rounded to the minor units of the currency of
+// the base rate.
+func (c *Charge) Total(baserate Money, checkin Date, nights, guests int) (Money, error) {
+	ranges := DateRanges{c.DateRange}
+	applies := func(d Date) bool {
+		return len(c.DateRange) == 0 || ranges.Contains(d)
+	}
+	first := truncateDate(checkin)
+	applied := 0
+	for i := 0; i < nights; i++ {
+		if applies(Date(first.AddDate(0, 0, i))) {
+			applied++
+		}
+	}
+
+	units := MinorUnits(baserate.Currency)
+	total := Money{NewDecimal(0, units), baserate.Currency}
+	var err error
+	switch c.Type {
+	case "percent":
+		if applied > 0 {
+			total.Value, err = mulQuo(baserate.Value, c.Amount, int64(applied), 100*int64(nights), units, RoundHalfUp)
+		}
+	case "fixed":
+		if c.Currency != "" && c.Currency != baserate.Currency {
+			return Money{}, fmt.Errorf("%w: %s charge for %s rate", ErrCurrencyMismatch, c.Currency, baserate.Currency)
+		}
+		count := 0
+		if c.Period == "per_night" {
+			count = applied
+		} else if applies(checkin) {
+			count = 1
+		}
+		if c.Basis == "per_person" {
+			count *= guests
+		}
+		total.Value, err = mulQuo(c.Amount, NewDecimal(int64(count), 0), 1, 1, units, RoundHalfUp)
+	default:
+		return Money{}, fmt.Errorf("gha: unknown charge type %q", c.Type)
+	}
+	if err != nil {
+		return Money{}, err
+	}
+	return total, nil
+}
+
+// Returns the sum of the given charges, see Charge.Total.
+func chargesTotal(charges []Charge, baserate Money, checkin Date, nights, guests int) (*Money, error) {
+	sum := Money{NewDecimal(0, MinorUnits(baserate.Currency)), baserate.Currency}
+	for i := range charges {
+		m, err := charges[i].Total(baserate, checkin, nights, guests)
+		if err != nil {
+			return nil, err
+		}
+		if sum, err = sum.Add(m); err != nil {
+			return nil, err
+		}
+	}
+	return &sum, nil
+}
+
+// Returns the given rate with <Tax> and <OtherFees> computed from its
+// <Baserate>, number of guests and the given itinerary. Values without
+// definition, e.g. <OtherFees> if the property has no <Fees>, are not
+// changed.
+//
+// The rate must be fully resolved, see Rate.Inherit.
+func (p *PropertyTaxes) Calculate(rate Rate, checkin Date, nights int) (Rate, error) {
+	if rate.Baserate == nil {
+		return rate, ErrNoBaserate
+	}
+	if nights < 1 {
+		return rate, fmt.Errorf("gha: length of stay must be at least 1 night, got %d", nights)
+	}
+
+	guests := rate.guests()
+	if p.Taxes != nil {
+		tax, err := chargesTotal(p.Taxes.Tax, *rate.Baserate, checkin, nights, guests)
+		if err != nil {
+			return rate, fmt.Errorf("tax: %w", err)
+		}
+		rate.Tax = tax
+	}
+	if p.Fees != nil {
+		fees, err := chargesTotal(p.Fees.Fee, *rate.Baserate, checkin, nights, guests)
+		if err != nil {
+			return rate, fmt.Errorf("fees: %w", err)
+		}
+		rate.OtherFees = fees
+	}
+	return rate, nil
+}
+
+// Sets <Tax> and <OtherFees> of every rate of the result that has or
+// inherits a <Baserate>: the rate of the <Result>, the rates in <Rates> and
+// the rates of its room bundles. Results of properties without taxes and
+// fees are not changed.
+func (i *TaxFeeInfo) Apply(r *Result) error {
+	p := i.Get(r.Property)
+	if p == nil || r.Nights == 0 {
+		return nil
+	}
+
+	apply := func(rate *Rate, parent Rate, path string) error {
+		resolved := rate.Inherit(parent)
+		if resolved.Baserate == nil {
+			return nil
+		}
+		c, err := p.Calculate(resolved, r.Checkin, int(r.Nights))
+		if err != nil {
+			return fmt.Errorf("gha: %s: %w", path, err)
+		}
+		if p.Taxes != nil {
+			rate.Tax = c.Tax
+		}
+		if p.Fees != nil {
+			rate.OtherFees = c.OtherFees
+		}
+		return nil
+	}
+	applyRates := func(base *Rate, rates *Rates, path string) error {
+		if err := apply(base, Rate{}, path); err != nil {
+			return err
+		}
+		if rates == nil {
+			return nil
+		}
+		for j := range rates.Rate {
+			if err := apply(&rates.Rate[j], *base, index(path+"/Rates/Rate", j)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := applyRates(&r.Rate, r.Rates, "Result"); err != nil {
+		return err
+	}
+	for j := range r.RoomBundle {
+		b := &r.RoomBundle[j]
+		if err := applyRates(&b.Rate, b.Rates, index("Result/RoomBundle", j)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reads a taxes and fees file.
+func DecodeTaxFeeInfo(r io.Reader) (*TaxFeeInfo, error) {
+	var i TaxFeeInfo
+	if err := decodeFile(r, &i); err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+// Writes the given definitions as taxes and fees file, including the XML
+// declaration.
+func EncodeTaxFeeInfo(w io.Writer, i *TaxFeeInfo) error {
+	return encodeFile(w, i)
+}
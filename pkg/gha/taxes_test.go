@@ -0,0 +1,163 @@
+package gha
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func readTaxFeeInfo(t *testing.T) *TaxFeeInfo {
+	var i *TaxFeeInfo
+	readTestFile(t, "TaxFeeInfo-Example.xml", func(r io.Reader) (err error) {
+		i, err = DecodeTaxFeeInfo(r)
+		return err
+	})
+	return i
+}
+
+func TestTaxFeeInfoFile(t *testing.T) {
+	got := readTaxFeeInfo(t)
+
+	want := []PropertyTaxes{
+		{
+			ID:    "1234",
+			Taxes: &Taxes{Tax: []Charge{{Type: "percent", Amount: MustParseDecimal("12")}}},
+			Fees: &Fees{Fee: []Charge{
+				{Type: "fixed", Basis: "per_person", Period: "per_night", Amount: MustParseDecimal("3.50"), Currency: "USD"},
+				{Type: "fixed", Amount: MustParseDecimal("25.00"), DateRange: []DateRange{
+					{Start: newDate("2018-07-01"), End: newDate("2018-08-31")},
+				}},
+			}},
+		},
+		{
+			ID: "5678",
+			Taxes: &Taxes{Tax: []Charge{{Type: "percent", Amount: MustParseDecimal("7.5"), DateRange: []DateRange{
+				{Start: newDate("2018-06-15")},
+			}}}},
+		},
+	}
+	if !reflect.DeepEqual(got.Property, want) {
+		printError(t, got.Property, want)
+	}
+
+	checkEncodeFile(t, "TaxFeeInfo-Example.xml", func(w io.Writer) error {
+		return EncodeTaxFeeInfo(w, got)
+	})
+}
+
+func TestChargeTotal(t *testing.T) {
+	baserate := Money{MustParseDecimal("300.00"), "USD"}
+	summer := []DateRange{{Start: newDate("2018-07-01"), End: newDate("2018-08-31")}}
+
+	tests := []struct {
+		name   string
+		charge Charge
+		want   string
+	}{
+		{"Percent", Charge{Type: "percent", Amount: MustParseDecimal("12")}, "36.00"},
+		{"Rounded percent", Charge{Type: "percent", Amount: MustParseDecimal("7.125")}, "21.38"},
+		{"Long percent", Charge{Type: "percent", Amount: MustParseDecimal("33.333333")}, "100.00"},
+		{"Long percent of nights in range", Charge{Type: "percent", Amount: MustParseDecimal("0.1234567890"), DateRange: summer}, "0.25"},
+		{"Percent of nights in range", Charge{Type: "percent", Amount: MustParseDecimal("10"), DateRange: summer}, "20.00"},
+		{"Fixed per stay", Charge{Type: "fixed", Amount: MustParseDecimal("25")}, "25.00"},
+		{"Fixed per stay out of range", Charge{Type: "fixed", Amount: MustParseDecimal("25"), DateRange: summer}, "0.00"},
+		{"Fixed per night", Charge{Type: "fixed", Period: "per_night", Amount: MustParseDecimal("4.25")}, "12.75"},
+		{"Fixed per person and night", Charge{Type: "fixed", Basis: "per_person", Period: "per_night", Amount: MustParseDecimal("3.50"), Currency: "USD"}, "31.50"},
+		{"Fixed per person and night in range", Charge{Type: "fixed", Basis: "per_person", Period: "per_night", Amount: MustParseDecimal("1"), DateRange: summer}, "6.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 3 nights from 2018-06-30, 2 of them in summer, 3 guests.
+			got, err := tt.charge.Total(baserate, newDate("2018-06-30"), 3, 3)
+			if err != nil {
+				t.Fatalf("Total failed with error: %v", err)
+			}
+			if want := *money(tt.want, "USD"); got != want {
+				t.Errorf("Total got %v, want: %v", got, want)
+			}
+		})
+	}
+
+	if _, err := (&Charge{Type: "fixed", Amount: MustParseDecimal("1"), Currency: "EUR"}).Total(baserate, newDate("2018-06-30"), 3, 3); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Total in other currency got error %v, want: %v", err, ErrCurrencyMismatch)
+	}
+	if _, err := (&Charge{Type: "flat"}).Total(baserate, newDate("2018-06-30"), 3, 3); err == nil {
+		t.Errorf("Total of unknown type got no error")
+	}
+}
+
+func TestTaxFeeInfoApply(t *testing.T) {
+	info := readTaxFeeInfo(t)
+
+	got := Result{
+		Property: Property{"1234"},
+		Checkin:  newDate("2018-06-30"),
+		Nights:   2,
+		Rate:     Rate{Baserate: money("200.00", "USD")},
+		Rates: &Rates{Rate: []Rate{
+			{RateRuleID: "family", Occupancy: 4},
+			{RateRuleID: "promo", Baserate: money("150.00", "USD")},
+		}},
+		RoomBundle: []RoomBundle{
+			{RoomID: "A", Rate: Rate{Baserate: money("250.00", "USD"), OccupancyDetails: &OccupancyDetails{NumAdults: 1}}},
+			{RoomID: "B"},
+		},
+	}
+	if err := info.Apply(&got); err != nil {
+		t.Fatalf("Apply failed with error: %v", err)
+	}
+
+	// Fees: 2 nights x 3.50 per guest, plus 25.00 for a stay from July.
+	want := Result{
+		Property: Property{"1234"},
+		Checkin:  newDate("2018-06-30"),
+		Nights:   2,
+		Rate:     Rate{Baserate: money("200.00", "USD"), Tax: money("24.00", "USD"), OtherFees: money("14.00", "USD")},
+		Rates: &Rates{Rate: []Rate{
+			{RateRuleID: "family", Occupancy: 4, Tax: money("24.00", "USD"), OtherFees: money("28.00", "USD")},
+			{RateRuleID: "promo", Baserate: money("150.00", "USD"), Tax: money("18.00", "USD"), OtherFees: money("14.00", "USD")},
+		}},
+		RoomBundle: []RoomBundle{
+			{RoomID: "A", Rate: Rate{
+				Baserate:         money("250.00", "USD"),
+				Tax:              money("30.00", "USD"),
+				OtherFees:        money("7.00", "USD"),
+				OccupancyDetails: &OccupancyDetails{NumAdults: 1},
+			}},
+			{RoomID: "B"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		printError(t, got, want)
+	}
+
+	july := Result{Property: Property{"1234"}, Checkin: newDate("2018-07-01"), Nights: 1, Rate: Rate{Baserate: money("100.00", "USD")}}
+	if err := info.Apply(&july); err != nil {
+		t.Fatalf("Apply failed with error: %v", err)
+	}
+	if !july.OtherFees.Equal(*money("32.00", "USD")) {
+		t.Errorf("OtherFees got %v, want: 32.00 USD", july.OtherFees)
+	}
+
+	// Taxes only; the fees of the result are kept.
+	other := Result{Property: Property{"5678"}, Checkin: newDate("2018-06-14"), Nights: 2,
+		Rate: Rate{Baserate: money("200.00", "EUR"), OtherFees: money("5.00", "EUR")}}
+	if err := info.Apply(&other); err != nil {
+		t.Fatalf("Apply failed with error: %v", err)
+	}
+	if !other.Tax.Equal(*money("7.50", "EUR")) || !other.OtherFees.Equal(*money("5.00", "EUR")) {
+		t.Errorf("Tax and OtherFees got %v and %v, want: 7.50 EUR and 5.00 EUR", other.Tax, other.OtherFees)
+	}
+
+	unknown := Result{Property: Property{"9999"}, Nights: 1, Rate: Rate{Baserate: money("100.00", "USD")}}
+	if err := info.Apply(&unknown); err != nil || unknown.Tax != nil {
+		t.Errorf("Apply to unknown property got %v, %v, want: no change", unknown.Tax, err)
+	}
+
+	mismatch := Result{Property: Property{"1234"}, Checkin: newDate("2018-06-30"), Nights: 1, Rate: Rate{Baserate: money("100.00", "EUR")}}
+	if err := info.Apply(&mismatch); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Apply got error %v, want: %v", err, ErrCurrencyMismatch)
+	}
+}
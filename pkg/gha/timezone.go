@@ -0,0 +1,93 @@
+package gha
+
+import (
+	"fmt"
+	"time"
+)
+
+// Time zones of properties, keyed by property ID. Check-in dates and the
+// refundable_until_time of a rate are in the local time of the hotel, so the
+// zone is needed to turn them into absolute times. Properties without an
+// entry are in UTC.
+type PropertyZones map[string]*time.Location
+
+// Returns the zones for the given IANA time zone names, keyed by property ID,
+// e.g. {"1234": "America/New_York"}.
+func LoadPropertyZones(names map[string]string) (PropertyZones, error) {
+	z := PropertyZones{}
+	for id, name := range names {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			return nil, fmt.Errorf("gha: time zone of property %q: %w", id, err)
+		}
+		z[id] = loc
+	}
+	return z, nil
+}
+
+// Returns the time zone of the given property.
+func (z PropertyZones) Location(p Property) *time.Location {
+	if loc := z[p.ID]; loc != nil {
+		return loc
+	}
+	return time.UTC
+}
+
+// Returns the absolute time until which the given rate of the property can be
+// fully refunded, see Refundable.Deadline.
+func (z PropertyZones) RefundDeadline(p Property, checkin Date, r *Refundable) (time.Time, bool) {
+	return r.Deadline(checkin, z.Location(p))
+}
+
+// Returns the absolute time after which a stay of the property with the given
+// check-in date can no longer be booked, see BookingCutoff.
+func (z PropertyZones) BookingCutoff(p Property, checkin Date, cutoff Time) time.Time {
+	return BookingCutoff(checkin, z.Location(p), cutoff)
+}
+
+// Returns true if the result can still be booked at the given time: the
+// booking cut-off of its check-in date has not passed and its <Rate> has not
+// expired.
+func (r *Result) Bookable(z PropertyZones, cutoff Time, now time.Time) bool {
+	return now.Before(z.BookingCutoff(r.Property, r.Checkin, cutoff)) && !r.Rate.Expired(now)
+}
+
+// Returns the start of the date in the given time zone.
+func (d Date) In(loc *time.Location) time.Time {
+	t := time.Time(d)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+// Returns the absolute time until which the rate can be fully refunded: the
+// refundable_until_time, in the time zone of the hotel, refundable_until_days
+// before the check-in date. If no time is set, the deadline is at midnight at
+// the start of that day.
+//
+// Returns false if the rate is not refundable.
+func (r *Refundable) Deadline(checkin Date, loc *time.Location) (time.Time, bool) {
+	if r == nil || !r.Available {
+		return time.Time{}, false
+	}
+	t := time.Time(checkin)
+	hour, min := r.RefundableUntilTime.Clock()
+	return time.Date(t.Year(), t.Month(), t.Day()-int(r.RefundableUntilDays), hour, min, 0, 0, loc), true
+}
+
+// Returns the absolute time after which a stay with the given check-in date
+// can no longer be booked: the given time of day on the check-in date, in
+// the time zone of the hotel. If no time is set, the cut-off is at midnight
+// at the end of the check-in date.
+func BookingCutoff(checkin Date, loc *time.Location, cutoff Time) time.Time {
+	if cutoff.IsZero() {
+		return checkin.In(loc).AddDate(0, 0, 1)
+	}
+	t := time.Time(checkin)
+	hour, min := cutoff.Clock()
+	return time.Date(t.Year(), t.Month(), t.Day(), hour, min, 0, 0, loc)
+}
+
+// Returns true if the <ExpirationTime> of the rate has passed at the given
+// time. Rates without expiration time do not expire.
+func (r *Rate) Expired(now time.Time) bool {
+	return r.ExpirationTime != nil && !now.Before(*r.ExpirationTime)
+}
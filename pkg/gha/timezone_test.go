@@ -0,0 +1,109 @@
+package gha
+
+import (
+	"testing"
+	"time"
+)
+
+func loadPropertyZones(t *testing.T) PropertyZones {
+	z, err := LoadPropertyZones(map[string]string{
+		"akl": "Pacific/Auckland",
+		"lax": "America/Los_Angeles",
+	})
+	if err != nil {
+		t.Fatalf("Loading time zones failed with error: %v", err)
+	}
+	return z
+}
+
+func TestLoadPropertyZones(t *testing.T) {
+	if _, err := LoadPropertyZones(map[string]string{"1234": "Mars/Olympus_Mons"}); err == nil {
+		t.Errorf("LoadPropertyZones of an unknown time zone did not fail")
+	}
+
+	z := loadPropertyZones(t)
+	if loc := z.Location(Property{"unknown"}); loc != time.UTC {
+		t.Errorf("Location of a property without time zone got %v, want: UTC", loc)
+	}
+}
+
+func TestRefundDeadline(t *testing.T) {
+	z := loadPropertyZones(t)
+
+	tests := []struct {
+		name       string
+		property   string
+		refundable *Refundable
+		want       string
+		ok         bool
+	}{
+		{
+			"Two days before check-in at 4 PM in Auckland",
+			"akl",
+			&Refundable{Available: true, RefundableUntilDays: 2, RefundableUntilTime: newTime("16:00")},
+			"2018-06-08T04:00:00Z",
+			true,
+		},
+		{
+			"Check-in day at midnight in Los Angeles",
+			"lax",
+			&Refundable{Available: true},
+			"2018-06-10T07:00:00Z",
+			true,
+		},
+		{
+			"Day before check-in in UTC",
+			"unknown",
+			&Refundable{Available: true, RefundableUntilDays: 1, RefundableUntilTime: newTime("23:59")},
+			"2018-06-09T23:59:00Z",
+			true,
+		},
+		{"Not refundable", "lax", &Refundable{RefundableUntilDays: 3}, "", false},
+		{"No refundable element", "lax", nil, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := z.RefundDeadline(Property{tt.property}, newDate("2018-06-10"), tt.refundable)
+			if ok != tt.ok {
+				t.Fatalf("RefundDeadline ok got %v, want: %v", ok, tt.ok)
+			}
+			if ok && got.UTC().Format(time.RFC3339) != tt.want {
+				t.Errorf("RefundDeadline got %v, want: %v", got.UTC().Format(time.RFC3339), tt.want)
+			}
+		})
+	}
+}
+
+func TestResultBookable(t *testing.T) {
+	z := loadPropertyZones(t)
+	at := func(value string) time.Time {
+		v, _ := time.Parse(time.RFC3339, value)
+		return v
+	}
+	expiration := at("2018-06-10T12:00:00Z")
+
+	tests := []struct {
+		name     string
+		property string
+		cutoff   Time
+		rate     Rate
+		now      string
+		want     bool
+	}{
+		{"Auckland before midnight", "akl", Time{}, Rate{}, "2018-06-10T11:59:00Z", true},
+		{"Auckland after midnight", "akl", Time{}, Rate{}, "2018-06-10T12:00:00Z", false},
+		{"Los Angeles on the evening of the check-in day", "lax", Time{}, Rate{}, "2018-06-11T06:59:00Z", true},
+		{"Los Angeles after the cut-off", "lax", newTime("18:00"), Rate{}, "2018-06-11T01:00:00Z", false},
+		{"Expired rate", "lax", Time{}, Rate{ExpirationTime: &expiration}, "2018-06-10T12:00:00Z", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Result{Rate: tt.rate, Property: Property{tt.property}, Checkin: newDate("2018-06-10")}
+			if got := r.Bookable(z, tt.cutoff, at(tt.now)); got != tt.want {
+				t.Errorf("Bookable got %v, want: %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,9 +1,10 @@
 package gha
 
 import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/xml"
 	"time"
-
-	cdt "github.com/f-go/go-custom-datetime"
 )
 
 // Container for descriptive information about rooms and packages
@@ -18,13 +19,80 @@ import (
 //   it's escaped). All Transaction messages containing HTML will
 //   be rejected.
 type Transaction struct {
-	ID        string             `xml:"id,attr"`        // required
-	Timestamp cdt.CustomDateTime `xml:"timestamp,attr"` // required
-	Partner   string             `xml:"partner,attr,omitempty"`
-	Result    []Result           `xml:",omitempty"`
+	ID        string   `xml:"id,attr"`        // required
+	Timestamp DateTime `xml:"timestamp,attr"` // required
+	Partner   string   `xml:"partner,attr,omitempty"`
+
+	PropertyDataSet []PropertyDataSet `xml:",omitempty"`
+	Result          []Result          `xml:",omitempty"`
+}
+
+// Returns a new random ID for a <Transaction> message. The ID is URL-safe
+// and unique with very high probability.
+func NewTransactionID() string {
+	b := make([]byte, 18)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// Container for the room and Room Bundle metadata of a single property.
+// Transaction messages with <PropertyDataSet> elements are the response to a
+// metadata <Query>.
+//
+// * The Action field:
+//   Set to "overlay" to replace all previously sent room and package data of
+//   the property. If not set, the data is merged with the data sent before.
+//
+// https://developers.google.com/hotels/hotel-prices/xml-reference/transaction-messages#PropertyDataSet
+type PropertyDataSet struct {
+	Action      string        `xml:"action,attr,omitempty"` // [overlay]
+	Property    Property      `xml:""`
+	RoomData    []RoomData    `xml:",omitempty"`
+	PackageData []PackageData `xml:",omitempty"`
+}
+
+// Container for the metadata of a room. The room is referenced by its
+// <RoomID> in <Result> and <RoomBundle> elements.
+type RoomData struct {
+	RoomID                string                 `xml:""`
+	Name                  *LocalizedText         `xml:",omitempty"`
+	Description           *LocalizedText         `xml:",omitempty"`
+	Capacity              uint8                  `xml:",omitempty"` // max. number of guests, min:1, max:99
+	PhotoURL              []PhotoURL             `xml:",omitempty"`
+	Occupancy             uint8                  `xml:",omitempty"`
+	OccupancySettings     *OccupancySettings     `xml:",omitempty"`
+	AllowablePointsOfSale *AllowablePointsOfSale `xml:",omitempty"`
+}
+
+// Container for the metadata of a package, i.e. everything that is
+// sold together with the room such as breakfast or parking. The package
+// is referenced by its <PackageID> in <RoomBundle> elements.
+type PackageData struct {
+	PackageID             string                 `xml:""`
+	Name                  *LocalizedText         `xml:",omitempty"`
+	Description           *LocalizedText         `xml:",omitempty"`
+	ChargeCurrency        ChargeCurrency         `xml:",omitempty"` // [deposit|hotel|installment|web]
+	Refundable            *Refundable            `xml:",omitempty"`
+	BreakfastIncluded     *Inclusion             `xml:",omitempty"`
+	InternetIncluded      *Inclusion             `xml:",omitempty"`
+	ParkingIncluded       *Inclusion             `xml:",omitempty"`
+	Occupancy             uint8                  `xml:",omitempty"`
+	OccupancySettings     *OccupancySettings     `xml:",omitempty"`
+	AllowablePointsOfSale *AllowablePointsOfSale `xml:",omitempty"`
+}
 
-	// PropertyDataSet PropertyDataSet `xml:"PropertyDataSet,omitempty"` will be added later
+// Container for a photo of a room.
+type PhotoURL struct {
+	URL     string         `xml:""`
+	Caption *LocalizedText `xml:",omitempty"`
+}
 
+// Container for restrictions on the guests of a room or package.
+type OccupancySettings struct {
+	MinOccupancy uint8 `xml:",omitempty"`
+	MinAge       uint8 `xml:",omitempty"`
 }
 
 // Container for pricing and availability updates in a <Transaction> message.
@@ -32,7 +100,7 @@ type Transaction struct {
 // Pricing data for a room's itinerary or a <RoomBundle> element that
 // defines Room Bundles and additional types of rooms for the property.
 // The <Result> element can also be used to remove itineraries from
-// inventory.
+// inventory, see <Unavailable> and NewUnavailableResult.
 //
 // * Note:
 //   Leave <BaseRate> for the <Result> empty for shared rooms (e.g., dorm-style
@@ -45,13 +113,93 @@ type Transaction struct {
 //
 // https://developers.google.com/hotels/hotel-prices/xml-reference/transaction-messages#Result
 type Result struct {
+	Property Property `xml:""`
+	RoomID   string   `xml:",omitempty"`
+	Checkin  Date     `xml:""`
+	Nights   uint8    `xml:",omitempty"`
+
 	Rate
 
-	Property Property       `xml:""`
-	Checkin  cdt.CustomDate `xml:""`
-	RoomID   string         `xml:",omitempty"`
-	Nights   uint8          `xml:",omitempty"`
-	Rates    *Rates         `xml:",omitempty"`
+	Rates *Rates `xml:",omitempty"`
+
+	RoomBundle  []RoomBundle `xml:",omitempty"`
+	Unavailable *Unavailable `xml:",omitempty"`
+}
+
+// Returns a <Result> that removes the itinerary from inventory for the given
+// reason, e.g. Unavailable{NoVacancy: true} for a sold out room.
+func NewUnavailableResult(property Property, checkin Date, nights uint8, reason Unavailable) Result {
+	return Result{
+		Property:    property,
+		Checkin:     checkin,
+		Nights:      nights,
+		Unavailable: &reason,
+	}
+}
+
+// Container for the reasons an itinerary is not available. A <Result> with
+// <Unavailable> has no prices; Google removes the itinerary from inventory.
+//
+// * NoVacancy:
+//   All rooms are sold out.
+// * MinNightStay / MaxNightStay:
+//   The length of stay is below the min. or above the max. number of nights.
+// * MinAdvancePurchase / MaxAdvancePurchase:
+//   The itinerary must be booked at least or at most the given number of days
+//   before check-in.
+// * ClosedToArrival / ClosedToDeparture:
+//   No check-in on the check-in date or no check-out on the check-out date.
+// * PriceIssue:
+//   The price cannot be determined, e.g. because it does not match the price
+//   on the landing page.
+type Unavailable struct {
+	NoVacancy          Flag       `xml:""`
+	MinNightStay       *Threshold `xml:",omitempty"`
+	MaxNightStay       *Threshold `xml:",omitempty"`
+	MinAdvancePurchase *Threshold `xml:",omitempty"`
+	MaxAdvancePurchase *Threshold `xml:",omitempty"`
+	ClosedToArrival    Flag       `xml:""`
+	ClosedToDeparture  Flag       `xml:""`
+	PriceIssue         Flag       `xml:""`
+}
+
+// Returns true if at least one reason is set.
+func (u *Unavailable) hasReason() bool {
+	return bool(u.NoVacancy || u.ClosedToArrival || u.ClosedToDeparture || u.PriceIssue) ||
+		u.MinNightStay != nil || u.MaxNightStay != nil ||
+		u.MinAdvancePurchase != nil || u.MaxAdvancePurchase != nil
+}
+
+// Number of nights or days of an <Unavailable> reason.
+type Threshold struct {
+	Value int `xml:"value,attr"`
+}
+
+// Container for the pricing of a room/package combination of the itinerary
+// defined by the parent <Result>. The room and the package are referenced
+// by <RoomID> and <PackageID> and are described by <RoomData> and
+// <PackageData> in a <PropertyDataSet>.
+//
+// Values set in the <RoomBundle> override the inclusions of the referenced
+// <PackageData>; an inclusion that is nil is not set, so an explicit false
+// is written as well.
+//
+// * The Rates field:
+//   Used only when there are multiple rates for the same room bundle. For
+//   example, you define conditional rates for a room bundle. Each <Rate>
+//   inherits pricing-related values from the <RoomBundle>.
+//
+// https://developers.google.com/hotels/hotel-prices/xml-reference/transaction-messages#RoomBundle
+type RoomBundle struct {
+	RoomID    string `xml:""`
+	PackageID string `xml:",omitempty"`
+
+	Rate
+
+	BreakfastIncluded *Inclusion `xml:",omitempty"`
+	InternetIncluded  *Inclusion `xml:",omitempty"`
+	ParkingIncluded   *Inclusion `xml:",omitempty"`
+	Rates             *Rates     `xml:",omitempty"`
 }
 
 // Container for one or more <Rate> blocks. Each <Rate> in <Rates>
@@ -137,9 +285,25 @@ type PointOfSale struct {
 // * If available is 0 or false, the other attributes are ignored. The rate does not
 //   display as refundable even if one or both of the other attributes is set.
 type Refundable struct {
-	Available           bool   `xml:"available,attr"`
-	RefundableUntilDays int32  `xml:"refundable_until_days,attr"`
-	RefundableUntilTime string `xml:"refundable_until_time,attr"`
+	Available           Bool  `xml:"available,attr"`
+	RefundableUntilDays int32 `xml:"refundable_until_days,attr"`
+	RefundableUntilTime Time  `xml:"refundable_until_time,attr"`
+}
+
+// Omits the attributes that are ignored: all but available if the rate is not
+// refundable, and refundable_until_time if it is not set.
+func (r Refundable) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type refundable struct {
+		Available           Bool   `xml:"available,attr"`
+		RefundableUntilDays *int32 `xml:"refundable_until_days,attr,omitempty"`
+		RefundableUntilTime Time   `xml:"refundable_until_time,attr"`
+	}
+	v := refundable{Available: r.Available}
+	if r.Available {
+		v.RefundableUntilDays = &r.RefundableUntilDays
+		v.RefundableUntilTime = r.RefundableUntilTime
+	}
+	return e.EncodeElement(v, start)
 }
 
 // Container that holds all rate information.
@@ -154,7 +318,7 @@ type Rate struct {
 	OtherFees             *Money                 `xml:",omitempty"`
 	ExpirationTime        *time.Time             `xml:",omitempty"`
 	Refundable            *Refundable            `xml:",omitempty"`
-	ChargeCurrency        string                 `xml:",omitempty"` // [deposit|hotel|installment|web]
+	ChargeCurrency        ChargeCurrency         `xml:",omitempty"` // [deposit|hotel|installment|web]
 	AllowablePointsOfSale *AllowablePointsOfSale `xml:",omitempty"`
 	Occupancy             uint8                  `xml:",omitempty"`
 	OccupancyDetails      *OccupancyDetails      `xml:",omitempty"`
@@ -164,3 +328,106 @@ type Rate struct {
 	Custom4               string                 `xml:",omitempty"`
 	Custom5               string                 `xml:",omitempty"`
 }
+
+// Writes the rate in the element order of Google's examples. <Occupancy> and
+// <OccupancyDetails> are written before the prices if <OccupancyDetails> is
+// set, as in the example of occupancy-based pricing, and after them
+// otherwise.
+func (r Rate) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeChildren(e, r.start(start), r.elements())
+}
+
+// Returns the given start element with the rate_rule_id attribute of the rate.
+func (r Rate) start(start xml.StartElement) xml.StartElement {
+	if r.RateRuleID != "" {
+		attr := append([]xml.Attr(nil), start.Attr...)
+		start.Attr = append(attr, xml.Attr{Name: xml.Name{Local: "rate_rule_id"}, Value: r.RateRuleID})
+	}
+	return start
+}
+
+// Returns the child elements of the rate, see Rate.MarshalXML.
+func (r Rate) elements() []child {
+	prices := []child{
+		{"Baserate", r.Baserate, r.Baserate != nil},
+		{"Tax", r.Tax, r.Tax != nil},
+		{"OtherFees", r.OtherFees, r.OtherFees != nil},
+		{"ExpirationTime", r.ExpirationTime, r.ExpirationTime != nil},
+		{"Refundable", r.Refundable, r.Refundable != nil},
+		{"ChargeCurrency", r.ChargeCurrency, r.ChargeCurrency != ""},
+	}
+	occupancy := []child{
+		{"Occupancy", r.Occupancy, r.Occupancy != 0},
+		{"OccupancyDetails", r.OccupancyDetails, r.OccupancyDetails != nil},
+	}
+	others := []child{
+		{"Custom1", r.Custom1, r.Custom1 != ""},
+		{"Custom2", r.Custom2, r.Custom2 != ""},
+		{"Custom3", r.Custom3, r.Custom3 != ""},
+		{"Custom4", r.Custom4, r.Custom4 != ""},
+		{"Custom5", r.Custom5, r.Custom5 != ""},
+		{"AllowablePointsOfSale", r.AllowablePointsOfSale, r.AllowablePointsOfSale != nil},
+	}
+	if r.OccupancyDetails != nil {
+		return append(append(occupancy, prices...), others...)
+	}
+	return append(append(prices, occupancy...), others...)
+}
+
+// Writes <Property>, <RoomID>, <Checkin> and <Nights> before the rate, as in
+// Google's examples, see Rate.MarshalXML.
+func (r Result) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	children := []child{
+		{"Property", r.Property, true},
+		{"RoomID", r.RoomID, r.RoomID != ""},
+		{"Checkin", r.Checkin, true},
+		{"Nights", r.Nights, r.Nights != 0},
+	}
+	children = append(children, r.Rate.elements()...)
+	children = append(children,
+		child{"Rates", r.Rates, r.Rates != nil},
+		child{"RoomBundle", r.RoomBundle, len(r.RoomBundle) > 0},
+		child{"Unavailable", r.Unavailable, r.Unavailable != nil},
+	)
+	return encodeChildren(e, r.Rate.start(start), children)
+}
+
+// Writes <RoomID> and <PackageID> before the rate and the inclusions after
+// it, as in Google's examples, see Rate.MarshalXML.
+func (b RoomBundle) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	children := []child{
+		{"RoomID", b.RoomID, true},
+		{"PackageID", b.PackageID, b.PackageID != ""},
+	}
+	children = append(children, b.Rate.elements()...)
+	children = append(children,
+		child{"BreakfastIncluded", b.BreakfastIncluded, b.BreakfastIncluded != nil},
+		child{"InternetIncluded", b.InternetIncluded, b.InternetIncluded != nil},
+		child{"ParkingIncluded", b.ParkingIncluded, b.ParkingIncluded != nil},
+		child{"Rates", b.Rates, b.Rates != nil},
+	)
+	return encodeChildren(e, b.Rate.start(start), children)
+}
+
+// Child element of a custom marshaller, which is written only if set.
+type child struct {
+	name  string
+	value interface{}
+	set   bool
+}
+
+// Writes the given element with the children that are set.
+func encodeChildren(e *xml.Encoder, start xml.StartElement, children []child) error {
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, c := range children {
+		if !c.set {
+			continue
+		}
+		if err := e.EncodeElement(c.value, xml.StartElement{Name: xml.Name{Local: c.name}}); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
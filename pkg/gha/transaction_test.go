@@ -7,7 +7,6 @@ import (
 	"reflect"
 	"testing"
 
-	cdt "github.com/f-go/go-custom-datetime"
 	"github.com/sergi/go-diff/diffmatchpatch"
 )
 
@@ -25,8 +24,8 @@ func TestTransactionMultiPropertyExample(t *testing.T) {
 		return
 	}
 
-	timestamp, _ := cdt.NewCustomDateTime("2017-07-23T16:20:00-04:00")
-	checkin, _ := cdt.NewCustomDate("2018-06-10")
+	timestamp, _ := NewDateTime("2017-07-23T16:20:00-04:00")
+	checkin, _ := NewDate("2018-06-10")
 	want = Transaction{
 		ID:        "42",
 		Timestamp: timestamp,
@@ -37,9 +36,9 @@ func TestTransactionMultiPropertyExample(t *testing.T) {
 				Checkin:  checkin,
 				Nights:   2,
 				Rate: Rate{
-					Baserate:  &Money{278.33, "USD"},
-					Tax:       &Money{25.12, "USD"},
-					OtherFees: &Money{2.00, "USD"},
+					Baserate:  money("278.33", "USD"),
+					Tax:       money("25.12", "USD"),
+					OtherFees: money("2.00", "USD"),
 					AllowablePointsOfSale: &AllowablePointsOfSale{
 						PointOfSale: []PointOfSale{
 							{"site1"},
@@ -53,9 +52,9 @@ func TestTransactionMultiPropertyExample(t *testing.T) {
 				Checkin:  checkin,
 				Nights:   2,
 				Rate: Rate{
-					Baserate:  &Money{299.98, "USD"},
-					Tax:       &Money{26.42, "USD"},
-					OtherFees: &Money{2.00, "USD"},
+					Baserate:  money("299.98", "USD"),
+					Tax:       money("26.42", "USD"),
+					OtherFees: money("2.00", "USD"),
 					AllowablePointsOfSale: &AllowablePointsOfSale{
 						PointOfSale: []PointOfSale{
 							{"otto"},
@@ -88,8 +87,8 @@ func TestTransactionMultiRateExample(t *testing.T) {
 		return
 	}
 
-	timestamp, _ := cdt.NewCustomDateTime("2020-07-23T16:20:00-04:00")
-	checkin, _ := cdt.NewCustomDate("2021-01-13")
+	timestamp, _ := NewDateTime("2020-07-23T16:20:00-04:00")
+	checkin, _ := NewDate("2021-01-13")
 	want = Transaction{
 		ID:        "42",
 		Timestamp: timestamp,
@@ -99,41 +98,41 @@ func TestTransactionMultiRateExample(t *testing.T) {
 				Checkin:  checkin,
 				Nights:   9,
 				Rate: Rate{
-					Baserate:  &Money{3196.1, "USD"},
-					Tax:       &Money{559.49, "USD"},
-					OtherFees: &Money{543.34, "USD"},
+					Baserate:  money("3196.1", "USD"),
+					Tax:       money("559.49", "USD"),
+					OtherFees: money("543.34", "USD"),
 					Occupancy: 2,
 				},
 				Rates: &Rates{
 					Rate: []Rate{
 						{
-							Baserate:  &Money{3196.1, "USD"},
-							Tax:       &Money{559.49, "USD"},
-							OtherFees: &Money{543.34, "USD"},
+							Baserate:  money("3196.1", "USD"),
+							Tax:       money("559.49", "USD"),
+							OtherFees: money("543.34", "USD"),
 							Occupancy: 1,
 						},
 						{
-							Baserate:  &Money{3196.1, "USD"},
-							Tax:       &Money{559.49, "USD"},
-							OtherFees: &Money{543.34, "USD"},
+							Baserate:  money("3196.1", "USD"),
+							Tax:       money("559.49", "USD"),
+							OtherFees: money("543.34", "USD"),
 							Occupancy: 3,
 						},
 						{
-							Baserate:  &Money{3196.1, "USD"},
-							Tax:       &Money{559.49, "USD"},
-							OtherFees: &Money{543.34, "USD"},
+							Baserate:  money("3196.1", "USD"),
+							Tax:       money("559.49", "USD"),
+							OtherFees: money("543.34", "USD"),
 							Occupancy: 4,
 						},
 						{
-							Baserate:  &Money{3196.1, "USD"},
-							Tax:       &Money{559.49, "USD"},
-							OtherFees: &Money{543.34, "USD"},
+							Baserate:  money("3196.1", "USD"),
+							Tax:       money("559.49", "USD"),
+							OtherFees: money("543.34", "USD"),
 							Occupancy: 5,
 						},
 						{
-							Baserate:  &Money{3196.1, "USD"},
-							Tax:       &Money{559.49, "USD"},
-							OtherFees: &Money{543.34, "USD"},
+							Baserate:  money("3196.1", "USD"),
+							Tax:       money("559.49", "USD"),
+							OtherFees: money("543.34", "USD"),
 							Occupancy: 6,
 						},
 					},
@@ -163,8 +162,8 @@ func TestTransactionBaseRateAndConditionalRate(t *testing.T) {
 		return
 	}
 
-	timestamp, _ := cdt.NewCustomDateTime("2017-07-18T16:20:00-04:00")
-	checkin, _ := cdt.NewCustomDate("2018-06-10")
+	timestamp, _ := NewDateTime("2017-07-18T16:20:00-04:00")
+	checkin, _ := NewDate("2018-06-10")
 	want = Transaction{
 		ID:        "42",
 		Timestamp: timestamp,
@@ -174,16 +173,16 @@ func TestTransactionBaseRateAndConditionalRate(t *testing.T) {
 				Checkin:  checkin,
 				Nights:   1,
 				Rate: Rate{
-					Baserate:  &Money{200.00, "USD"},
-					Tax:       &Money{20.00, "USD"},
-					OtherFees: &Money{1.00, "USD"},
+					Baserate:  money("200.00", "USD"),
+					Tax:       money("20.00", "USD"),
+					OtherFees: money("1.00", "USD"),
 				},
 				Rates: &Rates{
 					Rate: []Rate{
 						{
 							RateRuleID: "mobile",
-							Baserate:   &Money{180.00, "USD"},
-							Tax:        &Money{18.00, "USD"},
+							Baserate:   money("180.00", "USD"),
+							Tax:        money("18.00", "USD"),
 							Custom1:    "ratecode123",
 						},
 					},
@@ -213,8 +212,8 @@ func TestTransactionOneItineraryPricingForOneAdultChild(t *testing.T) {
 		return
 	}
 
-	timestamp, _ := cdt.NewCustomDateTime("2018-04-18T11:27:45-04:00")
-	checkin, _ := cdt.NewCustomDate("2018-06-20")
+	timestamp, _ := NewDateTime("2018-04-18T11:27:45-04:00")
+	checkin, _ := NewDate("2018-06-20")
 	want = Transaction{
 		ID:        "Wtdj8QoQIWcAAbaTGlIAAAC4",
 		Timestamp: timestamp,
@@ -224,9 +223,9 @@ func TestTransactionOneItineraryPricingForOneAdultChild(t *testing.T) {
 				Checkin:  checkin,
 				Nights:   1,
 				Rate: Rate{
-					Baserate:  &Money{62.18, "USD"},
-					Tax:       &Money{2.45, "USD"},
-					OtherFees: &Money{0.00, "USD"},
+					Baserate:  money("62.18", "USD"),
+					Tax:       money("2.45", "USD"),
+					OtherFees: money("0.00", "USD"),
 				},
 				Rates: &Rates{
 					Rate: []Rate{
@@ -241,9 +240,9 @@ func TestTransactionOneItineraryPricingForOneAdultChild(t *testing.T) {
 									},
 								},
 							},
-							Baserate:  &Money{42.61, "USD"},
-							Tax:       &Money{5.70, "USD"},
-							OtherFees: &Money{0.00, "USD"},
+							Baserate:  money("42.61", "USD"),
+							Tax:       money("5.70", "USD"),
+							OtherFees: money("0.00", "USD"),
 							Custom1:   "abc4",
 							AllowablePointsOfSale: &AllowablePointsOfSale{
 								PointOfSale: []PointOfSale{
@@ -263,3 +262,272 @@ func TestTransactionOneItineraryPricingForOneAdultChild(t *testing.T) {
 		t.Errorf("Query\ngot:  %v\nwant: %v\ndiff: %v", got.Result, want.Result, dmp.DiffPrettyText(diffs))
 	}
 }
+
+func TestTransactionPropertyDataSet(t *testing.T) {
+	var got, want Transaction
+
+	request, err := ioutil.ReadFile("./testdata/Transaction-PropertyDataSet.xml")
+	if err != nil {
+		t.Errorf("File reading error %v", err)
+		return
+	}
+
+	if err = xml.Unmarshal(request, &got); err != nil {
+		t.Errorf("Parsing request data failed with error: %v", err)
+		return
+	}
+
+	timestamp, _ := NewDateTime("2017-07-18T16:20:00-04:00")
+	want = Transaction{
+		ID:        "42",
+		Timestamp: timestamp,
+		PropertyDataSet: []PropertyDataSet{
+			{
+				Action:   "overlay",
+				Property: Property{"1234"},
+				RoomData: []RoomData{
+					{
+						RoomID: "RoomType101",
+						Name: &LocalizedText{
+							Text: []Text{
+								{"Standard Double Room", "en"},
+								{"Doppelzimmer Standard", "de"},
+							},
+						},
+						Description: &LocalizedText{
+							Text: []Text{
+								{"A double room with a view of the garden.", "en"},
+							},
+						},
+						Capacity: 2,
+						PhotoURL: []PhotoURL{
+							{
+								URL: "https://www.example.com/photos/room101.jpg",
+								Caption: &LocalizedText{
+									Text: []Text{
+										{"Bedroom", "en"},
+									},
+								},
+							},
+						},
+						Occupancy: 2,
+					},
+				},
+				PackageData: []PackageData{
+					{
+						PackageID: "Breakfast",
+						Name: &LocalizedText{
+							Text: []Text{
+								{"Bed and Breakfast", "en"},
+							},
+						},
+						ChargeCurrency: "hotel",
+						Refundable: &Refundable{
+							Available:           true,
+							RefundableUntilDays: 7,
+							RefundableUntilTime: newTime("18:00"),
+						},
+						BreakfastIncluded: NewInclusion(true),
+						InternetIncluded:  &Inclusion{Included: true, Words: true},
+						ParkingIncluded:   &Inclusion{Included: false, Words: true},
+						Occupancy:         2,
+						OccupancySettings: &OccupancySettings{
+							MinOccupancy: 1,
+							MinAge:       18,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		dmp := diffmatchpatch.New()
+		diffs := dmp.DiffMain(fmt.Sprintf("%v", got), fmt.Sprintf("%v", want), false)
+		t.Errorf("Query\ngot:  %v\nwant: %v\ndiff: %v", got, want, dmp.DiffPrettyText(diffs))
+	}
+}
+
+func TestTransactionRoomBundle(t *testing.T) {
+	var got, want Transaction
+
+	request, err := ioutil.ReadFile("./testdata/Transaction-RoomBundle.xml")
+	if err != nil {
+		t.Errorf("File reading error %v", err)
+		return
+	}
+
+	if err = xml.Unmarshal(request, &got); err != nil {
+		t.Errorf("Parsing request data failed with error: %v", err)
+		return
+	}
+
+	timestamp, _ := NewDateTime("2017-07-18T16:20:00-04:00")
+	checkin, _ := NewDate("2018-06-10")
+	want = Transaction{
+		ID:        "42",
+		Timestamp: timestamp,
+		Result: []Result{
+			{
+				Property: Property{"1234"},
+				Checkin:  checkin,
+				Nights:   2,
+				Rate: Rate{
+					Baserate:  money("200.00", "USD"),
+					Tax:       money("20.00", "USD"),
+					OtherFees: money("1.00", "USD"),
+				},
+				RoomBundle: []RoomBundle{
+					{
+						RoomID:    "RoomType101",
+						PackageID: "Breakfast",
+						Rate: Rate{
+							Baserate:  money("240.00", "USD"),
+							Tax:       money("24.00", "USD"),
+							OtherFees: money("1.00", "USD"),
+							Occupancy: 2,
+						},
+						BreakfastIncluded: NewInclusion(true),
+						Rates: &Rates{
+							Rate: []Rate{
+								{
+									RateRuleID: "mobile",
+									Baserate:   money("220.00", "USD"),
+									Tax:        money("22.00", "USD"),
+								},
+							},
+						},
+					},
+					{
+						RoomID: "RoomType102",
+						Rate: Rate{
+							Baserate:  money("300.00", "USD"),
+							Tax:       money("30.00", "USD"),
+							OtherFees: money("1.00", "USD"),
+							Refundable: &Refundable{
+								Available:           true,
+								RefundableUntilDays: 3,
+								RefundableUntilTime: newTime("12:00"),
+							},
+							Occupancy: 4,
+						},
+						ParkingIncluded: &Inclusion{Included: true, Words: true},
+					},
+				},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		dmp := diffmatchpatch.New()
+		diffs := dmp.DiffMain(fmt.Sprintf("%v", got), fmt.Sprintf("%v", want), false)
+		t.Errorf("Query\ngot:  %v\nwant: %v\ndiff: %v", got, want, dmp.DiffPrettyText(diffs))
+	}
+
+	// The room bundle must survive a marshal/unmarshal round trip.
+	data, err := xml.Marshal(got)
+	if err != nil {
+		t.Errorf("Marshal failed with error: %v", err)
+		return
+	}
+	var again Transaction
+	if err = xml.Unmarshal(data, &again); err != nil {
+		t.Errorf("Parsing marshalled data failed with error: %v", err)
+		return
+	}
+	if !reflect.DeepEqual(again.Result, want.Result) {
+		printError(t, again.Result, want.Result)
+	}
+}
+
+func TestRoomBundleInclusions(t *testing.T) {
+	b := RoomBundle{RoomID: "RoomType101", BreakfastIncluded: NewInclusion(false), ParkingIncluded: NewInclusion(true)}
+	got, err := xml.Marshal(b)
+	if err != nil {
+		t.Fatalf("Marshal data failed. %v", err)
+	}
+	want := "<RoomBundle><RoomID>RoomType101</RoomID><BreakfastIncluded>0</BreakfastIncluded>" +
+		"<ParkingIncluded>1</ParkingIncluded></RoomBundle>"
+	if string(got) != want {
+		t.Errorf("Marshal got %s, want: %s", got, want)
+	}
+
+	var again RoomBundle
+	if err := xml.Unmarshal(got, &again); err != nil {
+		t.Fatalf("Unmarshal data failed. %v", err)
+	}
+	if !reflect.DeepEqual(again, b) {
+		printError(t, again, b)
+	}
+}
+
+func TestTransactionUnavailable(t *testing.T) {
+	tx := readTransaction(t, "./testdata/Transaction-Unavailable.xml")
+
+	want := []Result{
+		NewUnavailableResult(Property{"1234"}, newDate("2018-06-10"), 1, Unavailable{NoVacancy: true}),
+		NewUnavailableResult(Property{"1234"}, newDate("2018-06-11"), 2, Unavailable{
+			MinNightStay:    &Threshold{3},
+			ClosedToArrival: true,
+		}),
+	}
+	if !reflect.DeepEqual(tx.Result, want) {
+		printError(t, tx.Result, want)
+	}
+
+	got, err := xml.Marshal(want[0])
+	if err != nil {
+		t.Fatalf("Marshal data failed. %v", err)
+	}
+	wantXML := "<Result><Property>1234</Property><Checkin>2018-06-10</Checkin><Nights>1</Nights>" +
+		"<Unavailable><NoVacancy></NoVacancy></Unavailable></Result>"
+	if string(got) != wantXML {
+		t.Errorf("Marshal got %s, want: %s", got, wantXML)
+	}
+}
+
+func TestRefundableMarshal(t *testing.T) {
+	tests := []struct {
+		name       string
+		refundable Refundable
+		want       string
+	}{
+		{
+			"Refundable",
+			Refundable{Available: true, RefundableUntilDays: 7, RefundableUntilTime: newTime("18:00")},
+			`<Refundable available="1" refundable_until_days="7" refundable_until_time="18:00"></Refundable>`,
+		},
+		{
+			"Refundable until check-in day midnight",
+			Refundable{Available: true},
+			`<Refundable available="1" refundable_until_days="0"></Refundable>`,
+		},
+		{
+			"Not refundable",
+			Refundable{RefundableUntilDays: 7, RefundableUntilTime: newTime("18:00")},
+			`<Refundable available="0"></Refundable>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := xml.Marshal(tt.refundable)
+			if err != nil {
+				t.Fatalf("Marshal data failed. %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Marshal got %s, want: %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewTransactionID(t *testing.T) {
+	a, b := NewTransactionID(), NewTransactionID()
+	if len(a) != 24 {
+		t.Errorf("len(ID) got %v, want: 24", len(a))
+	}
+	if a == b {
+		t.Errorf("IDs are not unique: %v", a)
+	}
+}
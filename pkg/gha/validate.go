@@ -0,0 +1,303 @@
+package gha
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// A violation of the rules for Transaction messages, e.g. a missing required
+// element or an attribute value out of range.
+type ValidationError struct {
+	// XPath of the invalid element or attribute, e.g.
+	// "Transaction/Result[1]/Rates/Rate[2]/@rate_rule_id".
+	Path    string
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	return e.Path + ": " + e.Message
+}
+
+// All violations found in a message.
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Checks the message against the rules Google enforces for Transaction
+// messages. The returned error is of type ValidationErrors and lists all
+// violations, or nil if the message is valid.
+func (t *Transaction) Validate() error {
+	v := validator{}
+	t.validate(&v, "Transaction")
+	return v.err()
+}
+
+// Checks the result against the rules for <Result> elements. See
+// Transaction.Validate.
+func (r *Result) Validate() error {
+	v := validator{}
+	r.validate(&v, "Result")
+	return v.err()
+}
+
+// Checks the room bundle against the rules for <RoomBundle> elements. See
+// Transaction.Validate.
+func (b *RoomBundle) Validate() error {
+	v := validator{}
+	b.validate(&v, "RoomBundle")
+	return v.err()
+}
+
+// Checks the rate against the rules for <Rate> elements. See
+// Transaction.Validate.
+func (r *Rate) Validate() error {
+	v := validator{}
+	r.validate(&v, "Rate")
+	return v.err()
+}
+
+// Checks the data set against the rules for <PropertyDataSet> elements. See
+// Transaction.Validate.
+func (p *PropertyDataSet) Validate() error {
+	v := validator{}
+	p.validate(&v, "PropertyDataSet")
+	return v.err()
+}
+
+// Collects the violations of a message.
+type validator struct {
+	errs ValidationErrors
+}
+
+func (v *validator) add(path, format string, args ...interface{}) {
+	v.errs = append(v.errs, ValidationError{path, fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+// Matches HTML tags, comments and entities, also when escaped. A "<" that
+// does not start a tag, e.g. in "1 < 2", is not matched.
+var htmlPattern = regexp.MustCompile(`(?i)</?[a-z][a-z0-9]*[\s/>]|<!(--|doctype)|&(lt|gt|amp|quot|apos|nbsp|#[0-9]+|#x[0-9a-f]+);`)
+
+// Reports the given text if it contains HTML.
+func (v *validator) text(path, s string) {
+	if htmlPattern.MatchString(s) {
+		v.add(path, "HTML is not allowed")
+	}
+}
+
+// Returns the XPath of the i-th element of a list, counting from 0.
+func index(path string, i int) string {
+	return fmt.Sprintf("%s[%d]", path, i+1)
+}
+
+func (t *Transaction) validate(v *validator, path string) {
+	if t.ID == "" {
+		v.add(path+"/@id", "is required")
+	}
+	if time.Time(t.Timestamp).IsZero() {
+		v.add(path+"/@timestamp", "is required")
+	}
+	if len(t.PropertyDataSet) == 0 && len(t.Result) == 0 {
+		v.add(path, "at least one of <PropertyDataSet> or <Result> is required")
+	}
+
+	for i := range t.PropertyDataSet {
+		t.PropertyDataSet[i].validate(v, index(path+"/PropertyDataSet", i))
+	}
+	for i := range t.Result {
+		t.Result[i].validate(v, index(path+"/Result", i))
+	}
+}
+
+func (r *Result) validate(v *validator, path string) {
+	if r.Property.ID == "" {
+		v.add(path+"/Property", "is required")
+	}
+	if time.Time(r.Checkin).IsZero() {
+		v.add(path+"/Checkin", "is required")
+	}
+	if r.Nights == 0 {
+		v.add(path+"/Nights", "is required")
+	}
+
+	if u := r.Unavailable; u != nil {
+		if !u.hasReason() {
+			v.add(path+"/Unavailable", "must have at least one reason")
+		}
+		if r.Baserate != nil || r.Rates != nil || len(r.RoomBundle) > 0 {
+			v.add(path+"/Unavailable", "must not be combined with prices")
+		}
+	}
+
+	r.Rate.validate(v, path)
+	r.Rates.validate(v, path+"/Rates")
+	for i := range r.RoomBundle {
+		r.RoomBundle[i].validate(v, index(path+"/RoomBundle", i))
+	}
+}
+
+func (b *RoomBundle) validate(v *validator, path string) {
+	if b.RoomID == "" {
+		v.add(path+"/RoomID", "is required")
+	}
+
+	b.Rate.validate(v, path)
+	b.Rates.validate(v, path+"/Rates")
+}
+
+func (r *Rates) validate(v *validator, path string) {
+	if r == nil {
+		return
+	}
+	for i := range r.Rate {
+		p := index(path+"/Rate", i)
+
+		// Rates that only differ in occupancy are not conditional.
+		rate := &r.Rate[i]
+		if rate.RateRuleID == "" && rate.Occupancy == 0 && rate.OccupancyDetails == nil {
+			v.add(p+"/@rate_rule_id", "is required for conditional rates")
+		}
+		rate.validate(v, p)
+	}
+}
+
+func (r *Rate) validate(v *validator, path string) {
+	v.text(path+"/@rate_rule_id", r.RateRuleID)
+	r.Baserate.validate(v, path+"/Baserate")
+	r.Tax.validate(v, path+"/Tax")
+	r.OtherFees.validate(v, path+"/OtherFees")
+	r.Refundable.validate(v, path+"/Refundable")
+	if r.ChargeCurrency != "" && !r.ChargeCurrency.Valid() {
+		v.add(path+"/ChargeCurrency", "must be one of deposit, hotel, installment or web, got %q", r.ChargeCurrency)
+	}
+	r.AllowablePointsOfSale.validate(v, path+"/AllowablePointsOfSale")
+	if r.OccupancyDetails != nil {
+		r.OccupancyDetails.validate(v, path+"/OccupancyDetails")
+		if r.Occupancy != 0 && int(r.Occupancy) != r.OccupancyDetails.guests() {
+			v.add(path+"/Occupancy", "must match the number of guests in <OccupancyDetails>")
+		}
+	}
+	for i, c := range []string{r.Custom1, r.Custom2, r.Custom3, r.Custom4, r.Custom5} {
+		v.text(fmt.Sprintf("%s/Custom%d", path, i+1), c)
+	}
+}
+
+// Matches an ISO 4217 currency code.
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+func (m *Money) validate(v *validator, path string) {
+	if m == nil {
+		return
+	}
+	if !currencyPattern.MatchString(m.Currency) {
+		v.add(path+"/@currency", "must be an ISO 4217 currency code, got %q", m.Currency)
+	}
+	if m.Value.Sign() < 0 {
+		v.add(path, "must not be negative")
+	}
+}
+
+func (r *Refundable) validate(v *validator, path string) {
+	if r == nil || !r.Available {
+		return
+	}
+	if r.RefundableUntilDays < 0 || r.RefundableUntilDays > 330 {
+		v.add(path+"/@refundable_until_days", "must be between 0 and 330, got %d", r.RefundableUntilDays)
+	}
+}
+
+func (p *AllowablePointsOfSale) validate(v *validator, path string) {
+	if p == nil {
+		return
+	}
+	for i, pos := range p.PointOfSale {
+		if pos.ID == "" {
+			v.add(index(path+"/PointOfSale", i)+"/@id", "is required")
+		}
+	}
+}
+
+func (o *OccupancyDetails) validate(v *validator, path string) {
+	if o.NumAdults < 1 || o.NumAdults > 20 {
+		v.add(path+"/NumAdults", "must be between 1 and 20, got %d", o.NumAdults)
+	}
+}
+
+// Returns the number of adults and children.
+func (o *OccupancyDetails) guests() int {
+	n := int(o.NumAdults)
+	if o.Children != nil {
+		n += len(o.Children.Child)
+	}
+	return n
+}
+
+func (p *PropertyDataSet) validate(v *validator, path string) {
+	if p.Property.ID == "" {
+		v.add(path+"/Property", "is required")
+	}
+	if p.Action != "" && p.Action != "overlay" {
+		v.add(path+"/@action", "must be overlay, got %q", p.Action)
+	}
+
+	for i := range p.RoomData {
+		d, dp := &p.RoomData[i], index(path+"/RoomData", i)
+		if d.RoomID == "" {
+			v.add(dp+"/RoomID", "is required")
+		}
+		d.Name.validate(v, dp+"/Name")
+		d.Description.validate(v, dp+"/Description")
+		if d.Capacity > 99 {
+			v.add(dp+"/Capacity", "must be between 1 and 99, got %d", d.Capacity)
+		}
+		for j, photo := range d.PhotoURL {
+			pp := index(dp+"/PhotoURL", j)
+			if photo.URL == "" {
+				v.add(pp+"/URL", "is required")
+			}
+			photo.Caption.validate(v, pp+"/Caption")
+		}
+		d.AllowablePointsOfSale.validate(v, dp+"/AllowablePointsOfSale")
+	}
+
+	for i := range p.PackageData {
+		d, dp := &p.PackageData[i], index(path+"/PackageData", i)
+		if d.PackageID == "" {
+			v.add(dp+"/PackageID", "is required")
+		}
+		d.Name.validate(v, dp+"/Name")
+		d.Description.validate(v, dp+"/Description")
+		if d.ChargeCurrency != "" && !d.ChargeCurrency.Valid() {
+			v.add(dp+"/ChargeCurrency", "must be one of deposit, hotel, installment or web, got %q", d.ChargeCurrency)
+		}
+		d.Refundable.validate(v, dp+"/Refundable")
+		d.AllowablePointsOfSale.validate(v, dp+"/AllowablePointsOfSale")
+	}
+}
+
+func (l *LocalizedText) validate(v *validator, path string) {
+	if l == nil {
+		return
+	}
+	for i, t := range l.Text {
+		p := index(path+"/Text", i)
+		if t.Language == "" {
+			v.add(p+"/@language", "is required")
+		}
+		v.text(p+"/@text", t.Text)
+	}
+}
@@ -0,0 +1,182 @@
+package gha
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func TestValidateExamples(t *testing.T) {
+	files := []string{
+		"./testdata/Transaction-BaseRateAndConditionalRate.xml",
+		"./testdata/Transaction-MultiPropertyExample.xml",
+		"./testdata/Transaction-MultiRateExample.xml",
+		"./testdata/Transaction-OneItineraryPricingForOneAdultChild.xml",
+		"./testdata/Transaction-PropertyDataSet.xml",
+		"./testdata/Transaction-RoomBundle.xml",
+		"./testdata/Transaction-Unavailable.xml",
+	}
+
+	for _, file := range files {
+		t.Run(file, func(t *testing.T) {
+			request, err := ioutil.ReadFile(file)
+			if err != nil {
+				t.Errorf("File reading error %v", err)
+				return
+			}
+
+			var tx Transaction
+			if err = xml.Unmarshal(request, &tx); err != nil {
+				t.Errorf("Parsing request data failed with error: %v", err)
+				return
+			}
+
+			if err := tx.Validate(); err != nil {
+				t.Errorf("Validate failed with error: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateViolations(t *testing.T) {
+	checkin, _ := NewDate("2018-06-10")
+	tx := Transaction{
+		Result: []Result{
+			{
+				Property: Property{"1234"},
+				Checkin:  checkin,
+				Nights:   1,
+				Rate: Rate{
+					Baserate:       money("200.00", "usd"),
+					ChargeCurrency: "card",
+					Refundable: &Refundable{
+						Available:           true,
+						RefundableUntilDays: 331,
+					},
+					Custom1: "<b>deal</b>",
+				},
+				Rates: &Rates{
+					Rate: []Rate{
+						{
+							Baserate: money("180.00", "USD"),
+						},
+						{
+							Occupancy: 3,
+							OccupancyDetails: &OccupancyDetails{
+								NumAdults: 21,
+							},
+						},
+					},
+				},
+				RoomBundle: []RoomBundle{
+					{
+						Rate: Rate{
+							Custom2: "Bed &amp; Breakfast",
+						},
+					},
+				},
+			},
+		},
+		PropertyDataSet: []PropertyDataSet{
+			{
+				Action: "replace",
+				RoomData: []RoomData{
+					{
+						Name: &LocalizedText{
+							Text: []Text{{Text: "Double Room"}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	want := ValidationErrors{
+		{"Transaction/@id", "is required"},
+		{"Transaction/@timestamp", "is required"},
+		{"Transaction/PropertyDataSet[1]/Property", "is required"},
+		{"Transaction/PropertyDataSet[1]/@action", "must be overlay, got \"replace\""},
+		{"Transaction/PropertyDataSet[1]/RoomData[1]/RoomID", "is required"},
+		{"Transaction/PropertyDataSet[1]/RoomData[1]/Name/Text[1]/@language", "is required"},
+		{"Transaction/Result[1]/Baserate/@currency", "must be an ISO 4217 currency code, got \"usd\""},
+		{"Transaction/Result[1]/Refundable/@refundable_until_days", "must be between 0 and 330, got 331"},
+		{"Transaction/Result[1]/ChargeCurrency", "must be one of deposit, hotel, installment or web, got \"card\""},
+		{"Transaction/Result[1]/Custom1", "HTML is not allowed"},
+		{"Transaction/Result[1]/Rates/Rate[1]/@rate_rule_id", "is required for conditional rates"},
+		{"Transaction/Result[1]/Rates/Rate[2]/OccupancyDetails/NumAdults", "must be between 1 and 20, got 21"},
+		{"Transaction/Result[1]/Rates/Rate[2]/Occupancy", "must match the number of guests in <OccupancyDetails>"},
+		{"Transaction/Result[1]/RoomBundle[1]/RoomID", "is required"},
+		{"Transaction/Result[1]/RoomBundle[1]/Custom2", "HTML is not allowed"},
+	}
+
+	err := tx.Validate()
+	got, ok := err.(ValidationErrors)
+	if !ok {
+		t.Fatalf("Validate got %T, want: ValidationErrors", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		printError(t, got.Error(), want.Error())
+	}
+}
+
+func TestValidateText(t *testing.T) {
+	tests := []struct {
+		text string
+		html bool
+	}{
+		{"<b>deal</b>", true},
+		{"Free Wi-Fi<br/>", true},
+		{"<p class=\"note\">Deal", true},
+		{"<!-- deal -->", true},
+		{"Bed &amp; Breakfast", true},
+		{"Rooms < breakfast included", false},
+		{"1 < 2 > 0", false},
+		{"<3 nights", false},
+		{"Bed & Breakfast", false},
+	}
+
+	for _, tt := range tests {
+		var v validator
+		v.text("Custom1", tt.text)
+		if got := len(v.errs) > 0; got != tt.html {
+			t.Errorf("text(%q) reported HTML: %v, want: %v", tt.text, got, tt.html)
+		}
+	}
+}
+
+func TestValidateUnavailable(t *testing.T) {
+	timestamp, _ := NewDateTime("2018-04-18T11:27:45-04:00")
+	tx := Transaction{
+		ID:        "42",
+		Timestamp: timestamp,
+		Result: []Result{
+			{
+				Property:    Property{"1234"},
+				Checkin:     newDate("2018-06-10"),
+				Nights:      1,
+				Rate:        Rate{Baserate: money("200.00", "USD")},
+				Unavailable: &Unavailable{},
+			},
+		},
+	}
+
+	want := ValidationErrors{
+		{"Transaction/Result[1]/Unavailable", "must have at least one reason"},
+		{"Transaction/Result[1]/Unavailable", "must not be combined with prices"},
+	}
+	got, _ := tx.Validate().(ValidationErrors)
+	if !reflect.DeepEqual(got, want) {
+		printError(t, got.Error(), want.Error())
+	}
+}
+
+func TestValidateEmptyTransaction(t *testing.T) {
+	timestamp, _ := NewDateTime("2017-07-18T16:20:00-04:00")
+	tx := Transaction{ID: "42", Timestamp: timestamp}
+
+	want := "Transaction: at least one of <PropertyDataSet> or <Result> is required"
+	if err := tx.Validate(); err == nil || err.Error() != want {
+		t.Errorf("Validate got %v, want: %v", err, want)
+	}
+}